@@ -13,11 +13,11 @@ import (
 	"slices"
 	"strings"
 
-	"github.com/pterm/pterm"
+	"github.com/nais/naistrix/internal/color"
+	"github.com/nais/naistrix/output"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
-	"golang.org/x/term"
 )
 
 // Application represents a CLI application with a set of commands.
@@ -34,6 +34,10 @@ type Application struct {
 	// writer is the output destination for the OutputWriter used in the application. Defaults to os.Stdout.
 	writer io.Writer
 
+	// errWriter is the output destination for diagnostic and status messages written by the OutputWriter used in the
+	// application. Defaults to os.Stderr.
+	errWriter io.Writer
+
 	// output is the output writer used in the application.
 	output *OutputWriter
 
@@ -59,6 +63,18 @@ type Application struct {
 
 	// config is the Viper configuration instance used for managing application configuration.
 	config *viper.Viper
+
+	// outputFormats are the output formats available for the --output flag.
+	outputFormats outputFormats
+
+	// pager tells whether long output from commands should be shown in a pager.
+	pager bool
+
+	// theme defines the styles of the inline tags used in output.
+	theme Theme
+
+	// markdownDescriptions tells whether the descriptions of commands should be rendered as markdown in the help output.
+	markdownDescriptions bool
 }
 
 // ApplicationOptionFunc is a function that configures an [Application].
@@ -75,6 +91,14 @@ func ApplicationWithWriter(w io.Writer) ApplicationOptionFunc {
 	}
 }
 
+// ApplicationWithErrorWriter sets the output destination for diagnostic and status messages written by the
+// [OutputWriter] used in the application, like warnings and debug output. This defaults to [os.Stderr].
+func ApplicationWithErrorWriter(w io.Writer) ApplicationOptionFunc {
+	return func(a *Application) {
+		a.errWriter = w
+	}
+}
+
 // ApplicationWithDefaultsCommandName sets the name of the "defaults" command.
 func ApplicationWithDefaultsCommandName(name string) ApplicationOptionFunc {
 	return func(a *Application) {
@@ -82,6 +106,33 @@ func ApplicationWithDefaultsCommandName(name string) ApplicationOptionFunc {
 	}
 }
 
+// ApplicationWithOutputFormat registers an output format that the end-user can select using the global --output flag.
+// Registering a format with the same name as one of the built-in formats replaces the built-in format.
+func ApplicationWithOutputFormat(name OutputFormat, fn OutputFormatFunc) ApplicationOptionFunc {
+	return func(a *Application) {
+		a.outputFormats[name] = fn
+	}
+}
+
+// ApplicationWithPager makes the application show long output from commands in a pager. The pager command is read from
+// the PAGER environment variable, is run using the shell, and defaults to "less -FRX". The pager is only used when the
+// output is written to a terminal, and is started when the command writes output, so streamed output is shown right
+// away. less exits right away when the output fits in the terminal. The end-user can disable the pager using the global
+// --no-pager flag.
+func ApplicationWithPager() ApplicationOptionFunc {
+	return func(a *Application) {
+		a.pager = true
+	}
+}
+
+// ApplicationWithMarkdownDescriptions makes the application render the [Command.Description] of commands as markdown
+// in the help output, using [output.Markdown]. The markup is removed when styling is disabled.
+func ApplicationWithMarkdownDescriptions() ApplicationOptionFunc {
+	return func(a *Application) {
+		a.markdownDescriptions = true
+	}
+}
+
 // runOptions holds options for running the application with the Run() method, and is manipulated via RunOptionFunc
 // functions.
 type runOptions struct {
@@ -147,20 +198,33 @@ func NewApplication(name, title, version string, opts ...ApplicationOptionFunc)
 		title:   title,
 		version: version,
 		flags: &GlobalFlags{
+			Color:  ColorModeAuto,
 			Config: userConfigDir + "/config.yaml",
 		},
 		config:              v,
 		defaultsCommandName: "defaults",
+		outputFormats:       defaultOutputFormats(),
+		theme:               DefaultTheme(),
 	}
 
 	for _, opt := range opts {
 		opt(app)
 	}
 
+	theme, err := app.theme.compile()
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid theme: %w", err)
+	}
+	color.SetTheme(theme)
+
 	if app.writer == nil {
 		app.writer = os.Stdout
 	}
 
+	if app.errWriter == nil {
+		app.errWriter = os.Stderr
+	}
+
 	cobra.EnableTraverseRunHooks = true
 
 	app.rootCommand = &cobra.Command{
@@ -174,19 +238,47 @@ func NewApplication(name, title, version string, opts ...ApplicationOptionFunc)
 				return fmt.Errorf("failed to initialize configuration: %w", err)
 			}
 
-			if err := syncViperToFlags(app.flags, app.config); err != nil {
-				return fmt.Errorf("failed to sync sticky flags: %w", err)
+			if err := syncViperToFlags(app.flags, app.rootCommand.PersistentFlags(), app.config); err != nil {
+				return err
 			}
 
 			for _, f := range app.additionalGlobalFlags {
-				if err := syncViperToFlags(f, app.config); err != nil {
-					return fmt.Errorf("failed to sync additional sticky flags: %w", err)
+				if err := syncViperToFlags(f, app.rootCommand.PersistentFlags(), app.config); err != nil {
+					return err
+				}
+			}
+
+			if err := validateFlagValues(app.config, append([]any{app.flags}, app.additionalGlobalFlags...)...); err != nil {
+				return err
+			}
+
+			if overrides := app.config.GetStringMapString(themeConfigKey); len(overrides) > 0 {
+				theme, err := app.theme.merge(overrides).compile()
+				if err != nil {
+					return Errorf("Invalid theme in the configuration file: %v", err)
 				}
+				color.SetTheme(theme)
 			}
 
-			// Disable styling if NoColors flag is set or if stdout is not a TTY
-			if app.flags.NoColors || !term.IsTerminal(int(os.Stdout.Fd())) { // #nosec G115
-				pterm.DisableStyling()
+			if app.flags.IsQuiet() && app.flags.IsVerbose() {
+				return Errorf("The --quiet and --verbose flags can not be used together")
+			}
+
+			if err := validateOutputFormat(app.flags.Output, cmd, app.outputFormats); err != nil {
+				return err
+			}
+
+			if !app.flags.Color.valid() {
+				return Errorf("Invalid color mode %q, must be one of: %s", app.flags.Color, strings.Join(colorModes, ", "))
+			}
+
+			app.setupStyling()
+
+			// cobra writes the notice for deprecated flags to stdout, so the notice is written here instead, and left out
+			// in quiet mode. Values from the configuration file are not warned about, since the end-user would be warned
+			// on every run.
+			if cmd.Flags().Changed("no-colors") {
+				app.output.Warnln("The --no-colors flag is deprecated, use --color=never instead")
 			}
 
 			return nil
@@ -194,12 +286,35 @@ func NewApplication(name, title, version string, opts ...ApplicationOptionFunc)
 	}
 	app.rootCommand.CompletionOptions.SetDefaultShellCompDirective(cobra.ShellCompDirectiveNoFileComp)
 	app.rootCommand.SetOut(app.writer)
+	app.rootCommand.SetErr(app.errWriter)
+	app.rootCommand.SetFlagErrorFunc(flagError)
 	app.output = NewOutputWriter(app.writer, &app.flags.VerboseLevel)
+	app.output.errWriter = app.errWriter
+	app.output.quiet = &app.flags.Quiet
+	app.output.format = &app.flags.Output
+	app.output.formats = app.outputFormats
+	app.output.columns = &app.flags.Columns
+	app.output.sortBy = &app.flags.Sort
+	app.output.filters = &app.flags.Filter
+	app.output.pager = app.pager
+	app.output.noPager = &app.flags.NoPager
 
 	if err := setupFlags(app.rootCommand, nil, app.flags, app.rootCommand.PersistentFlags()); err != nil {
 		return nil, nil, fmt.Errorf("failed to setup application flags: %w", err)
 	}
 
+	if err := app.rootCommand.PersistentFlags().MarkHidden("no-colors"); err != nil {
+		return nil, nil, fmt.Errorf("failed to hide the no-colors flag: %w", err)
+	}
+
+	if err := app.setupOutputFlag(); err != nil {
+		return nil, nil, fmt.Errorf("failed to setup output flag: %w", err)
+	}
+
+	if app.markdownDescriptions {
+		app.setupMarkdownHelp()
+	}
+
 	if err := app.AddCommand(defaultsCommand(app.defaultsCommandName, app.config)); err != nil {
 		return nil, nil, fmt.Errorf("failed to add defaults command: %w", err)
 	}
@@ -207,7 +322,10 @@ func NewApplication(name, title, version string, opts ...ApplicationOptionFunc)
 	return app, app.flags, nil
 }
 
-// AddCommand adds one or more commands to the application.
+// AddCommand adds one or more commands to the application. An error is returned if a command, or any of its
+// subcommands, has a flag with the same name or shorthand as a global flag. Note that this includes the --quiet,
+// --output, --columns, --sort, --filter, --no-pager and --color flags, which means that commands that have their own
+// flags with these names must rename them.
 func (a *Application) AddCommand(cmd *Command, cmds ...*Command) error {
 	all := append([]*Command{cmd}, cmds...)
 	a.commands = append(a.commands, all...)
@@ -241,6 +359,10 @@ func (a *Application) AddCommand(cmd *Command, cmds ...*Command) error {
 			return fmt.Errorf("failed to initialize command %q: %w", c.Name, err)
 		}
 
+		if err := a.globalFlagConflict(c.cobraCmd); err != nil {
+			return fmt.Errorf("command %q %w", c.Name, err)
+		}
+
 		a.rootCommand.AddCommand(c.cobraCmd)
 
 		if err := collectTopLevelAliases(c, aliases); err != nil {
@@ -257,6 +379,65 @@ func (a *Application) AddCommand(cmd *Command, cmds ...*Command) error {
 	return nil
 }
 
+// setupOutputFlag registers auto-completion for the global --output flag, and makes sure the help and usage output
+// lists the output formats supported by the command the output is shown for.
+func (a *Application) setupOutputFlag() error {
+	err := a.rootCommand.RegisterFlagCompletionFunc(
+		"output",
+		func(cmd *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+			formats := commandOutputFormats(cmd, a.outputFormats)
+			completions := make([]string, len(formats))
+			for i, f := range formats {
+				completions[i] = string(f)
+			}
+			return completions, cobra.ShellCompDirectiveNoFileComp
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	setUsage := func(cmd *cobra.Command) {
+		if f := cmd.Flag("output"); f != nil {
+			f.Usage = outputFlagUsage(commandOutputFormats(cmd, a.outputFormats))
+		}
+	}
+
+	help := a.rootCommand.HelpFunc()
+	a.rootCommand.SetHelpFunc(func(cmd *cobra.Command, args []string) {
+		setUsage(cmd)
+		help(cmd, args)
+	})
+
+	usage := a.rootCommand.UsageFunc()
+	a.rootCommand.SetUsageFunc(func(cmd *cobra.Command) error {
+		setUsage(cmd)
+		return usage(cmd)
+	})
+
+	return nil
+}
+
+// setupMarkdownHelp makes the help output render the long description of commands as markdown.
+func (a *Application) setupMarkdownHelp() {
+	help := a.rootCommand.HelpFunc()
+	a.rootCommand.SetHelpFunc(func(cmd *cobra.Command, args []string) {
+		// the help output is shown without running the persistent pre-run hooks, where styling is normally set up
+		a.setupStyling()
+
+		long := cmd.Long
+		defer func() { cmd.Long = long }()
+
+		if long != "" {
+			var sb strings.Builder
+			_ = output.NewMarkdown(&sb).Render(long)
+			cmd.Long = strings.TrimSuffix(sb.String(), "\n")
+		}
+
+		help(cmd, args)
+	})
+}
+
 // Output returns the [OutputWriter] used in the application.
 func (a *Application) Output() *OutputWriter {
 	return a.output
@@ -301,17 +482,54 @@ func (a *Application) createAliasCommand(alias string, c *Command) *cobra.Comman
 }
 
 // AddGlobalFlags adds global flags to the application. These flags will be available for all subcommands of the
-// application. The passed flags must be a pointer to a struct where each field represents a flag.
+// application. The passed flags must be a pointer to a struct where each field represents a flag. An error is returned
+// if an already added command has a flag with the same name or shorthand as one of the flags.
 func (a *Application) AddGlobalFlags(flags any) error {
 	if err := setupFlags(a.rootCommand, nil, flags, a.rootCommand.PersistentFlags()); err != nil {
 		return fmt.Errorf("failed to setup global flags: %w", err)
 	}
 
+	for _, c := range a.commands {
+		if err := a.globalFlagConflict(c.cobraCmd); err != nil {
+			return fmt.Errorf("command %q %w", c.Name, err)
+		}
+	}
+
 	a.additionalGlobalFlags = append(a.additionalGlobalFlags, flags)
 
 	return nil
 }
 
+// globalFlagConflict returns an error describing the first flag of the command, or of any of its subcommands, with the
+// same name or shorthand as a global flag, or nil if there are none. Cobra lets flags with the same name shadow the
+// global flags, which means that the global flags would silently be ignored when running the command, and panics when
+// the command is executed if the shorthands collide.
+func (a *Application) globalFlagConflict(cmd *cobra.Command) error {
+	var err error
+	check := func(f *pflag.Flag) {
+		if err != nil {
+			return
+		}
+
+		// Cobra merges the global flags into the flag sets of the commands, so skip the global flags themselves.
+		if g := a.rootCommand.PersistentFlags().Lookup(f.Name); g != nil && g != f {
+			err = fmt.Errorf("has a flag with the same name as the global flag %q", f.Name)
+		} else if f.Shorthand != "" {
+			if g := a.rootCommand.PersistentFlags().ShorthandLookup(f.Shorthand); g != nil && g != f {
+				err = fmt.Errorf("has a flag (%q) with the same shorthand as the global flag %q: -%s", f.Name, g.Name, f.Shorthand)
+			}
+		}
+	}
+	cmd.Flags().VisitAll(check)
+	cmd.PersistentFlags().VisitAll(check)
+	for _, sub := range cmd.Commands() {
+		if err == nil {
+			err = a.globalFlagConflict(sub)
+		}
+	}
+	return err
+}
+
 // Run executes the application.
 func (a *Application) Run(opts ...RunOptionFunc) error {
 	ro := &runOptions{}
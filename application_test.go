@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"github.com/nais/naistrix"
+	"github.com/pterm/pterm"
 )
 
 var noop = func(context.Context, *naistrix.Arguments, *naistrix.OutputWriter) error {
@@ -167,6 +168,132 @@ func TestApplicationValidation(t *testing.T) {
 			t.Fatalf("expected error message to contain %q, got: %q", contains, err.Error())
 		}
 	})
+
+	t.Run("subcommand flag shadowing a global flag", func(t *testing.T) {
+		app, _, err := naistrix.NewApplication("app", "some app", "v0.0.0")
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+
+		err = app.AddCommand(&naistrix.Command{
+			Name:  "list",
+			Title: "Some list command",
+			SubCommands: []*naistrix.Command{{
+				Name:  "apps",
+				Title: "List applications",
+				Flags: &struct {
+					Columns []string `name:"columns"`
+				}{},
+				RunFunc: noop,
+			}},
+		})
+		if err == nil {
+			t.Fatalf("expected error, got nil")
+		}
+
+		if contains := `same name as the global flag "columns"`; !strings.Contains(err.Error(), contains) {
+			t.Fatalf("expected error message to contain %q, got: %q", contains, err.Error())
+		}
+	})
+
+	t.Run("command flag with the same shorthand as a global flag", func(t *testing.T) {
+		app, _, err := naistrix.NewApplication("app", "some app", "v0.0.0")
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+
+		err = app.AddCommand(&naistrix.Command{
+			Name:  "cmd",
+			Title: "Some command",
+			Flags: &struct {
+				Level int `name:"level" short:"v"`
+			}{},
+			RunFunc: noop,
+		})
+		if contains := `same shorthand as the global flag "verbose"`; err == nil || !strings.Contains(err.Error(), contains) {
+			t.Fatalf("expected error message to contain %q, got: %v", contains, err)
+		}
+	})
+
+	t.Run("command flag with the -o shorthand", func(t *testing.T) {
+		app, _, err := naistrix.NewApplication("app", "some app", "v0.0.0", naistrix.ApplicationWithWriter(&bytes.Buffer{}))
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+
+		flags := &struct {
+			Owner string `name:"owner" short:"o"`
+		}{}
+		err = app.AddCommand(&naistrix.Command{
+			Name:    "cmd",
+			Title:   "Some command",
+			Flags:   flags,
+			RunFunc: noop,
+		})
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+
+		if err := app.Run(naistrix.RunWithArgs([]string{"cmd", "-o", "team"})); err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+
+		if flags.Owner != "team" {
+			t.Fatalf("expected owner to be %q, got: %q", "team", flags.Owner)
+		}
+	})
+
+	t.Run("global flag with the same shorthand as a command flag", func(t *testing.T) {
+		app, _, err := naistrix.NewApplication("app", "some app", "v0.0.0")
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+
+		err = app.AddCommand(&naistrix.Command{
+			Name:  "cmd",
+			Title: "Some command",
+			Flags: &struct {
+				Environment string `name:"environment" short:"e"`
+			}{},
+			RunFunc: noop,
+		})
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+
+		err = app.AddGlobalFlags(&struct {
+			Env string `name:"env" short:"e"`
+		}{})
+		if contains := `same shorthand as the global flag "env"`; err == nil || !strings.Contains(err.Error(), contains) {
+			t.Fatalf("expected error message to contain %q, got: %v", contains, err)
+		}
+	})
+
+	t.Run("global flag shadowed by a command flag", func(t *testing.T) {
+		app, _, err := naistrix.NewApplication("app", "some app", "v0.0.0")
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+
+		err = app.AddCommand(&naistrix.Command{
+			Name:  "cmd",
+			Title: "Some command",
+			StickyFlags: &struct {
+				Team string `name:"team"`
+			}{},
+			RunFunc: noop,
+		})
+		if err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+
+		err = app.AddGlobalFlags(&struct {
+			Team string `name:"team"`
+		}{})
+		if contains := `same name as the global flag "team"`; err == nil || !strings.Contains(err.Error(), contains) {
+			t.Fatalf("expected error message to contain %q, got: %v", contains, err)
+		}
+	})
 }
 
 func TestExecutedCommands(t *testing.T) {
@@ -494,3 +621,38 @@ func TestExecuteTopLevelAlias(t *testing.T) {
 		t.Fatalf("expected version to be %q, got: %q", expected, buf.String())
 	}
 }
+
+func TestApplicationWithMarkdownDescriptions(t *testing.T) {
+	defer pterm.EnableStyling()
+
+	buf := &bytes.Buffer{}
+	app, _, err := naistrix.NewApplication(
+		"app",
+		"title",
+		"v1.2.3",
+		naistrix.ApplicationWithWriter(buf),
+		naistrix.ApplicationWithMarkdownDescriptions(),
+	)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	err = app.AddCommand(&naistrix.Command{
+		Name:        "cmd",
+		Title:       "Command",
+		Description: "## Details\n\nUse **--wait** to wait for the rollout, see the [docs](https://doc.nais.io).",
+		RunFunc:     noop,
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	if err := app.Run(naistrix.RunWithArgs([]string{"cmd", "--help", "--color=never"})); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	expected := "Command\n\nDetails\n\nUse --wait to wait for the rollout, see the docs (https://doc.nais.io).\n"
+	if help := buf.String(); !strings.HasPrefix(help, expected) {
+		t.Fatalf("expected help text to start with %q, got: %q", expected, help)
+	}
+}
@@ -44,7 +44,8 @@ type Command struct {
 	Title string
 
 	// Description is a detailed description of the command, shown in the help output. When set, it will be prefixed
-	// with the [Command.Title] field.
+	// with the [Command.Title] field. Use the [ApplicationWithMarkdownDescriptions] option to render the description as
+	// markdown.
 	Description string
 
 	// Deprecated marks the command as deprecated. When set, the commands [Command.RunFunc] is never executed, instead
@@ -89,6 +90,11 @@ type Command struct {
 	// Examples are examples of how to use the command. The examples are shown in the help output in the added order.
 	Examples []Example
 
+	// OutputFormats are the output formats supported by the command when rendering data with [OutputWriter.Render].
+	// The first format is used as the default when the end-user does not select a format using the global --output
+	// flag. When empty, all registered output formats are supported.
+	OutputFormats []OutputFormat
+
 	// cobraCmd is the internal cobra.Command for the Command.
 	cobraCmd *cobra.Command
 }
@@ -284,10 +290,20 @@ func (c *Command) cobraRun(out *OutputWriter) func(*cobra.Command, []string) err
 		}
 	}
 
+	out = out.forCommand(c.OutputFormats)
 	return func(cmd *cobra.Command, args []string) error {
 		// Silence the usage for errors that might occur in the RunFunc of the command
 		cmd.SilenceUsage = true
-		return c.RunFunc(cmd.Context(), newArguments(c.Args, args), out)
+
+		out, closePager := out.withPager()
+		out, stopIndicators := out.withIndicators(cmd.Context())
+		err := c.RunFunc(cmd.Context(), newArguments(c.Args, args), out)
+		stopIndicators()
+		if perr := closePager(); err == nil {
+			err = perr
+		}
+
+		return err
 	}
 }
 
@@ -319,6 +335,10 @@ func (c *Command) validate() error {
 		return fmt.Errorf("either RunFunc, SubCommands or Deprecated must be set for command: %v", c.Name)
 	}
 
+	if len(c.OutputFormats) > 0 && len(c.SubCommands) > 0 {
+		return fmt.Errorf("parent command %q can not declare output formats", c.Name)
+	}
+
 	return c.validateArgs()
 }
 
@@ -328,6 +348,12 @@ func (c *Command) init(cmd string, out *OutputWriter, usageTemplate string, conf
 		return err
 	}
 
+	for _, f := range c.OutputFormats {
+		if _, ok := out.formats[f]; !ok {
+			return fmt.Errorf("command %q declares unknown output format %q", c.Name, f)
+		}
+	}
+
 	cmd = cmd + " " + c.Name
 
 	example, err := c.cobraExample(cmd)
@@ -347,14 +373,24 @@ func (c *Command) init(cmd string, out *OutputWriter, usageTemplate string, conf
 		ValidArgsFunction: c.autocomplete(),
 		PersistentPreRunE: func(co *cobra.Command, args []string) error {
 			if c.Flags != nil {
-				if err := syncViperToFlags(c.Flags, config); err != nil {
-					return fmt.Errorf("failed to sync command flags: %w", err)
+				if err := syncViperToFlags(c.Flags, c.cobraCmd.Flags(), config); err != nil {
+					return err
 				}
 			}
 
 			if c.StickyFlags != nil {
-				if err := syncViperToFlags(c.StickyFlags, config); err != nil {
-					return fmt.Errorf("failed to sync sticky flags: %w", err)
+				if err := syncViperToFlags(c.StickyFlags, c.cobraCmd.PersistentFlags(), config); err != nil {
+					return err
+				}
+			}
+
+			if err := validateFlagValues(config, c.Flags, c.StickyFlags); err != nil {
+				return err
+			}
+
+			if co == c.cobraCmd {
+				if err := validateFlagConstraints(co.Flags(), config); err != nil {
+					return err
 				}
 			}
 
@@ -372,6 +408,12 @@ func (c *Command) init(cmd string, out *OutputWriter, usageTemplate string, conf
 		},
 	}
 
+	if len(c.OutputFormats) > 0 {
+		c.cobraCmd.Annotations = map[string]string{
+			outputFormatsAnnotation: joinOutputFormats(c.OutputFormats, ","),
+		}
+	}
+
 	if c.RunFunc == nil {
 		// The internal cobraCmd will always be runnable since we are hijacking the RunE function to make sure an error
 		// is returned if an unknown subcommand is invoked. Because of this the usage template will always treat the
@@ -8,11 +8,13 @@ import (
 	"maps"
 	"os"
 	"path/filepath"
+	"reflect"
 	"slices"
 	"sort"
 
 	"github.com/MakeNowJust/heredoc/v2"
 	"github.com/nais/naistrix/input"
+	"github.com/nais/naistrix/output"
 	"github.com/spf13/viper"
 )
 
@@ -43,7 +45,7 @@ func defaultsSet(config *viper.Viper) *Command {
 			{Name: "value"},
 		},
 		Title:       "Set a configuration value.",
-		Description: "Set a configuration value in the configuration file. This value will be used as default for relevant flags throughout the application.",
+		Description: "Set a configuration value in the configuration file. This value will be used as default for relevant flags throughout the application. Use the --verbose flag to show the changes made to the configuration file.",
 		AutoCompleteFunc: func(_ context.Context, args *Arguments, _ string) ([]string, string) {
 			settings, err := getSettingsFromConfigFile(config)
 			if err != nil {
@@ -74,7 +76,7 @@ func defaultsSet(config *viper.Viper) *Command {
 			key := args.Get("key")
 			value := args.Get("value")
 
-			out.Printf("Set <info>%s</info> = <info>%s</info>\n", key, value)
+			out.Infof("Set %s = %s\n", key, value)
 
 			v := viper.New()
 			v.SetConfigFile(configFilePath)
@@ -82,13 +84,14 @@ func defaultsSet(config *viper.Viper) *Command {
 				return fmt.Errorf("unable to read configuration file %q: %w", configFilePath, err)
 			}
 
+			before := v.AllSettings()
 			v.Set(key, value)
 			if err := v.WriteConfig(); err != nil {
 				return fmt.Errorf("unable to save configuration file: %w", err)
 			}
 
-			out.Println("Configuration file updated")
-			return nil
+			out.Successln("Configuration file updated")
+			return showConfigChange(out, configFilePath, before, v.AllSettings())
 		},
 	}
 }
@@ -109,12 +112,11 @@ func defaultsGet(defaultsCommandName string, config *viper.Viper) *Command {
 			for _, key := range args.GetRepeatable("key") {
 				value, ok := settings[key]
 				if !ok {
-					out.Printf("No such configuration key: <info>%s</info>, create the value using <info>%s set %s <value></info>\n", key, defaultsCommandName, key)
+					out.Warnf("No such configuration key: %s, create the value using %s set %s <value>\n", key, defaultsCommandName, key)
 					continue
 				}
 
 				out.Printf("<info>%s</info> = <info>%v</info>\n", key, value)
-
 			}
 			return nil
 		},
@@ -133,8 +135,8 @@ func defaultsList(defaultsCommandName string, config *viper.Viper) *Command {
 			}
 
 			if len(settings) == 0 {
-				out.Printf("The configuration file <info>%s</info> is empty, or it does not yet exist\n", config.ConfigFileUsed())
-				out.Printf("Use the <info>%s set <key> <value></info> command to set configuration values\n", defaultsCommandName)
+				out.Infof("The configuration file %s is empty, or it does not yet exist\n", config.ConfigFileUsed())
+				out.Infof("Use the %s set <key> <value> command to set configuration values\n", defaultsCommandName)
 				return nil
 			}
 
@@ -151,9 +153,11 @@ func defaultsList(defaultsCommandName string, config *viper.Viper) *Command {
 			})
 
 			values = append([][]string{{"Key", "Value"}}, values...)
-			out.Printf("The following configuration values are set in <info>%s</info>:\n\n", config.ConfigFileUsed())
-			_ = out.Table().Render(values)
-			out.Printf("\nUse the <info>%[1]s set <key> <value></info> command to update or create values, or the <info>%[1]s unset <key>[, <key>]</info> command to remove values\n", defaultsCommandName)
+			out.Infof("The following configuration values are set in %s:\n", config.ConfigFileUsed())
+			if err := out.Table().Render(values); err != nil {
+				return err
+			}
+			out.Infof("Use the %[1]s set <key> <value> command to update or create values, or the %[1]s unset <key>[, <key>] command to remove values\n", defaultsCommandName)
 			return nil
 		},
 	}
@@ -163,7 +167,7 @@ func defaultsUnset(config *viper.Viper) *Command {
 	return &Command{
 		Name:             "unset",
 		Title:            "Unset one or more configuration values.",
-		Description:      "This command removes one or more configuration values from the configuration file completely.",
+		Description:      "This command removes one or more configuration values from the configuration file completely. Use the --verbose flag to show the changes made to the configuration file.",
 		Args:             []Argument{{Name: "key", Repeatable: true}},
 		AutoCompleteFunc: autoCompleteConfigurationKeys(config),
 		RunFunc: func(_ context.Context, args *Arguments, out *OutputWriter) error {
@@ -172,20 +176,21 @@ func defaultsUnset(config *viper.Viper) *Command {
 				return fmt.Errorf("unable to read configuration file: %w", err)
 			}
 
+			before := maps.Clone(settings)
 			updated := false
 			for _, key := range args.GetRepeatable("key") {
 				value, ok := settings[key]
 				if !ok {
-					out.Printf("No such configuration key: <info>%s</info>\n", key)
+					out.Warnf("No such configuration key: %s\n", key)
 					continue
 				}
-				out.Printf("Unset <info>%s</info> (value: <info>%v</info>)\n", key, value)
+				out.Infof("Unset %s (value: %v)\n", key, value)
 				delete(settings, key)
 				updated = true
 			}
 
 			if !updated {
-				out.Println("Nothing to update")
+				out.Infoln("Nothing to update")
 				return nil
 			}
 
@@ -199,12 +204,32 @@ func defaultsUnset(config *viper.Viper) *Command {
 				return fmt.Errorf("unable to save configuration file: %w", err)
 			}
 
-			out.Println("Configuration file updated")
-			return nil
+			out.Successln("Configuration file updated")
+			return showConfigChange(out, config.ConfigFileUsed(), before, settings)
 		},
 	}
 }
 
+// showConfigChange shows the changes made to the configuration file as a diff, when running in verbose mode. Like the
+// other verbose output, the diff is written to the error destination.
+func showConfigChange(out *OutputWriter, configFilePath string, before, after map[string]any) error {
+	if !out.IsVerbose() || reflect.DeepEqual(before, after) {
+		return nil
+	}
+
+	// an empty configuration file is shown as having no lines, rather than as an empty YAML map
+	change := output.Change{}
+	if len(before) > 0 {
+		change.Before = before
+	}
+	if len(after) > 0 {
+		change.After = after
+	}
+
+	_, _ = fmt.Fprintln(out.errWriter)
+	return output.NewDiff(out.errWriter, output.DiffWithLabels(configFilePath, configFilePath)).Render(change)
+}
+
 // ensureDirectoryExists tries to create the directory that will hold the Viper configuration file.
 func ensureDirectoryExists(dir string) error {
 	return os.MkdirAll(dir, 0o750)
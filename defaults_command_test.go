@@ -13,52 +13,128 @@ func TestConfig(t *testing.T) {
 	tempDir := t.TempDir()
 	configPath := filepath.Join(tempDir, "config.yaml")
 
-	if got, err := runCommand(configPath, "defaults list"); err != nil {
+	if _, got, err := runCommand(configPath, "defaults list"); err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	} else if contains := "is empty, or it does not yet exist"; !strings.Contains(got, contains) {
 		t.Fatalf("expected output to contain %q, got %q", contains, got)
 	}
 
-	if got, err := runCommand(configPath, "defaults set expected_key expected_value"); err != nil {
+	if _, got, err := runCommand(configPath, "defaults set expected_key expected_value"); err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	} else if contains := "Set expected_key = expected_value"; !strings.Contains(got, contains) {
 		t.Fatalf("expected output to contain %q, got %q", contains, got)
 	}
 
-	if got, err := runCommand(configPath, "defaults get expected_key"); err != nil {
+	if got, _, err := runCommand(configPath, "defaults get expected_key"); err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	} else if contains := "expected_key = expected_value"; !strings.Contains(got, contains) {
 		t.Fatalf("expected output to contain %q, got %q", contains, got)
 	}
 
-	if got, err := runCommand(configPath, "defaults unset expected_key"); err != nil {
+	if _, got, err := runCommand(configPath, "defaults unset expected_key"); err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	} else if contains := "Unset expected_key (value: expected_value)"; !strings.Contains(got, contains) {
 		t.Fatalf("expected output to contain %q, got %q", contains, got)
 	}
 
-	if got, err := runCommand(configPath, "defaults get expected_key"); err != nil {
+	if _, got, err := runCommand(configPath, "defaults get expected_key"); err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	} else if contains := "No such configuration key: expected_key"; !strings.Contains(got, contains) {
 		t.Fatalf("expected output to contain %q, got %q", contains, got)
 	}
 }
 
-func runCommand(configPath, args string) (string, error) {
-	argSlice := []string{"--no-colors", "--config", configPath}
+func TestConfig_Quiet(t *testing.T) {
+	tempDir := t.TempDir()
+	configPath := filepath.Join(tempDir, "config.yaml")
+
+	tests := []struct {
+		args     string
+		expected string
+	}{
+		{args: "defaults list", expected: ""},
+		{args: "defaults set key value", expected: ""},
+		{args: "defaults get key", expected: "key = value\n"},
+		{args: "defaults list", expected: "Key | Value\n-----------\nkey | value\n"},
+		{args: "defaults unset key", expected: ""},
+		{args: "defaults unset key", expected: ""},
+		{args: "defaults get key", expected: ""},
+	}
+
+	for _, tt := range tests {
+		got, status, err := runCommand(configPath, "--quiet "+tt.args)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.args, err)
+		}
+
+		if got != tt.expected {
+			t.Fatalf("%s: expected output to be %q, got %q", tt.args, tt.expected, got)
+		}
+
+		if status != "" {
+			t.Fatalf("%s: expected no status output, got %q", tt.args, status)
+		}
+	}
+}
+
+func TestConfig_VerboseDiff(t *testing.T) {
+	tempDir := t.TempDir()
+	configPath := filepath.Join(tempDir, "config.yaml")
+	header := "\n--- " + configPath + "\n+++ " + configPath + "\n"
+
+	tests := []struct {
+		args     string
+		expected string
+	}{
+		{
+			args: "defaults set team my-team",
+			expected: "INFO: Set team = my-team\nSUCCESS: Configuration file updated\n" + header +
+				"@@ -0,0 +1 @@\n+team: my-team\n",
+		},
+		{
+			args: "defaults set cluster dev",
+			expected: "INFO: Set cluster = dev\nSUCCESS: Configuration file updated\n" + header +
+				"@@ -1 +1,2 @@\n+cluster: dev\n team: my-team\n",
+		},
+		{
+			args: "defaults unset team",
+			expected: "INFO: Unset team (value: my-team)\nSUCCESS: Configuration file updated\n" + header +
+				"@@ -1,2 +1 @@\n cluster: dev\n-team: my-team\n",
+		},
+	}
+
+	for _, tt := range tests {
+		got, status, err := runCommand(configPath, "-v "+tt.args)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.args, err)
+		}
+
+		if got != "" {
+			t.Fatalf("%s: expected no output, got %q", tt.args, got)
+		}
+
+		if status != tt.expected {
+			t.Fatalf("%s: expected status output to be %q, got %q", tt.args, tt.expected, status)
+		}
+	}
+}
+
+func runCommand(configPath, args string) (string, string, error) {
+	argSlice := []string{"--color=never", "--config", configPath}
 	argSlice = append(argSlice, strings.Split(args, " ")...)
 
-	var outputBuffer bytes.Buffer
+	var outputBuffer, errorBuffer bytes.Buffer
 	app, _, err := naistrix.NewApplication(
 		"test",
 		"test application",
 		"v0.6.9",
 		naistrix.ApplicationWithWriter(&outputBuffer),
+		naistrix.ApplicationWithErrorWriter(&errorBuffer),
 	)
 	if err != nil {
-		return "", err
+		return "", "", err
 	}
 
 	err = app.Run(naistrix.RunWithArgs(argSlice))
-	return outputBuffer.String(), err
+	return outputBuffer.String(), errorBuffer.String(), err
 }
@@ -133,8 +133,9 @@ func (a *Application) GenerateDocs(opts ...GenerateDocsOptionFunc) error {
 	}()
 
 	w := NewOutputWriter(options.outputWriter, new(OutputVerbosityLevelNormal))
+	w.errWriter = options.outputWriter
 	for _, cmd := range a.commands {
-		if err := generateDocsForCommand(cmd, root, options.strict, w); err != nil {
+		if err := generateDocsForCommand(cmd, root, options.strict, a.outputFormats, w); err != nil {
 			return fmt.Errorf("failed to generate docs for command %q: %v", cmd.Name, err)
 		}
 	}
@@ -143,7 +144,7 @@ func (a *Application) GenerateDocs(opts ...GenerateDocsOptionFunc) error {
 }
 
 // generateDocsForCommand generates docs for a command in a recursive manner.
-func generateDocsForCommand(cmd *Command, root *os.Root, strict bool, ow *OutputWriter) error {
+func generateDocsForCommand(cmd *Command, root *os.Root, strict bool, formats outputFormats, ow *OutputWriter) error {
 	if cmd.Deprecated != nil {
 		ow.Infof("skipping deprecated command %q\n", cmd.cobraCmd.CommandPath())
 		return nil
@@ -167,6 +168,10 @@ func generateDocsForCommand(cmd *Command, root *os.Root, strict bool, ow *Output
 		return fmt.Errorf("failed to initialize flags: %w", err)
 	}
 
+	if f := cmd.cobraCmd.Flag("output"); f != nil {
+		f.Usage = outputFlagUsage(commandOutputFormats(cmd.cobraCmd, formats))
+	}
+
 	var synopsis string
 	if len(cmd.SubCommands) > 0 {
 		synopsis = cmd.cobraCmd.CommandPath() + " <command>"
@@ -204,7 +209,7 @@ func generateDocsForCommand(cmd *Command, root *os.Root, strict bool, ow *Output
 	}
 
 	for _, s := range cmd.SubCommands {
-		if err := generateDocsForCommand(s, root, strict, ow); err != nil {
+		if err := generateDocsForCommand(s, root, strict, formats, ow); err != nil {
 			return fmt.Errorf("failed to generate docs for subcommand %q: %v", s.Name, err)
 		}
 	}
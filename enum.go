@@ -0,0 +1,215 @@
+package naistrix
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// Enum is a flag value that only accepts one of a fixed set of values. Use [NewEnum] to create one, typically as the
+// default value of a field in a flags struct:
+//
+//	flags := &struct {
+//		Environment naistrix.Enum[Environment] `name:"environment"`
+//	}{
+//		Environment: naistrix.NewEnum(EnvironmentDev, EnvironmentDev, EnvironmentProd),
+//	}
+//
+// As an alternative, string fields can be restricted to a fixed set of values using the `enum` struct tag, like
+// `enum:"dev,prod"`. In both cases the values are validated when parsed, including values from the configuration file
+// and environment variables, the allowed values are suggested when auto-completing the flag, and listed in the help
+// output and the generated documentation.
+type Enum[T ~string] struct {
+	value   T
+	allowed []T
+}
+
+// NewEnum creates a new [Enum] that accepts the allowed values, set to the provided default value.
+func NewEnum[T ~string](defaultValue T, allowed ...T) Enum[T] {
+	return Enum[T]{
+		value:   defaultValue,
+		allowed: allowed,
+	}
+}
+
+// Value returns the current value.
+func (e Enum[T]) Value() T {
+	return e.value
+}
+
+// String returns the current value. This method satisfies the [FlagValue] interface.
+func (e Enum[T]) String() string {
+	return string(e.value)
+}
+
+// Set sets the value, which must be one of the allowed values. This method satisfies the [FlagValue] interface.
+func (e *Enum[T]) Set(value string) error {
+	if !slices.Contains(e.allowed, T(value)) {
+		return &enumError{value: value, allowed: e.enumValues()}
+	}
+
+	e.value = T(value)
+	return nil
+}
+
+// Type returns the name of the type shown in help output. This method satisfies the [FlagValue] interface.
+func (e *Enum[T]) Type() string {
+	return "string"
+}
+
+// enumValues returns the allowed values.
+func (e *Enum[T]) enumValues() []string {
+	ret := make([]string, len(e.allowed))
+	for i, v := range e.allowed {
+		ret[i] = string(v)
+	}
+	return ret
+}
+
+// enumFlag is implemented by the values of flags that only accept a fixed set of values.
+type enumFlag interface {
+	enumValues() []string
+}
+
+// enumValue wraps the value of a flag with the `enum` struct tag, and only accepts the allowed values. The values of
+// slice flags are validated one by one.
+type enumValue struct {
+	pflag.Value
+	allowed []string
+}
+
+// Set validates the value before passing it on to the wrapped value.
+func (e *enumValue) Set(value string) error {
+	if err := validateEnum(e.allowed, e.split(value)...); err != nil {
+		return err
+	}
+
+	return e.Value.Set(value)
+}
+
+// split returns the values in value, which holds comma-separated values for slice flags.
+func (e *enumValue) split(value string) []string {
+	if _, ok := e.Value.(pflag.SliceValue); !ok {
+		return []string{value}
+	}
+
+	ret := make([]string, 0)
+	for v := range strings.SplitSeq(value, ",") {
+		ret = append(ret, strings.TrimSpace(v))
+	}
+	return ret
+}
+
+func (e *enumValue) enumValues() []string {
+	return e.allowed
+}
+
+// enumError is returned when the value of an enum flag is not one of the allowed values.
+type enumError struct {
+	value   string
+	allowed []string
+}
+
+func (e *enumError) Error() string {
+	return fmt.Sprintf("invalid value %q, must be one of: %s", e.value, strings.Join(e.allowed, ", "))
+}
+
+// validateEnum returns an [enumError] for the first of the values that is not one of the allowed values.
+func validateEnum(allowed []string, values ...string) error {
+	for _, v := range values {
+		if !slices.Contains(allowed, v) {
+			return &enumError{value: v, allowed: allowed}
+		}
+	}
+	return nil
+}
+
+// getFlagEnum retrieves the allowed values from the `enum` struct tag, or returns nil if not set.
+func getFlagEnum(field reflect.StructField) []string {
+	return getTagValues(field, "enum")
+}
+
+// setupEnumFlag restricts the flag to the values allowed by the `enum` struct tag, or by the flag value itself when it
+// is an [Enum]. The allowed values are registered for auto-completion, unless the flag has an auto-completer, and
+// added to the usage of the flag. Flags without a fixed set of values are left as is.
+func setupEnumFlag(cmd *cobra.Command, field reflect.StructField, flag *pflag.Flag, autoComplete bool) error {
+	if allowed := getFlagEnum(field); allowed != nil {
+		switch flag.Value.Type() {
+		case "string", "stringSlice":
+		default:
+			return fmt.Errorf("the enum tag is only supported for string and []string flags")
+		}
+
+		flag.Value = &enumValue{Value: flag.Value, allowed: allowed}
+	}
+
+	e, ok := flag.Value.(enumFlag)
+	if !ok {
+		return nil
+	}
+
+	allowed := e.enumValues()
+	if len(allowed) == 0 {
+		return fmt.Errorf("enum flag without allowed values")
+	}
+
+	appendFlagUsage(flag, "One of: "+strings.Join(allowed, ", ")+".")
+
+	if !autoComplete {
+		return nil
+	}
+
+	return cmd.RegisterFlagCompletionFunc(flag.Name, cobra.FixedCompletions(allowed, cobra.ShellCompDirectiveNoFileComp))
+}
+
+// syncEnumFlag validates that a value synced from the configuration file or environment variables is allowed by the
+// `enum` struct tag of the field.
+func syncEnumFlag(field reflect.StructField, value reflect.Value) error {
+	allowed := getFlagEnum(field)
+	if allowed == nil {
+		return nil
+	}
+
+	switch value.Kind() {
+	case reflect.String:
+		return validateEnum(allowed, value.String())
+	case reflect.Slice:
+		values := make([]string, value.Len())
+		for i := range value.Len() {
+			values[i] = value.Index(i).String()
+		}
+		return validateEnum(allowed, values...)
+	default:
+		return nil
+	}
+}
+
+// invalidFlagValue returns the error reported to the end-user when the value of a flag can not be parsed.
+func invalidFlagValue(name string, err error) Error {
+	if e, ok := errors.AsType[*enumError](err); ok {
+		return Errorf("Invalid value %q for the --%s flag, must be one of: %s", e.value, name, strings.Join(e.allowed, ", "))
+	}
+
+	return Errorf("Invalid value for the --%s flag: %v", name, err)
+}
+
+// flagError converts errors from parsing flags on the command line to errors reported to the end-user, when the value
+// of an enum flag is not allowed, or when the value of a flag returns an [Error] itself.
+func flagError(_ *cobra.Command, err error) error {
+	if e, ok := errors.AsType[Error](err); ok {
+		return e
+	}
+
+	if e, ok := errors.AsType[*pflag.InvalidValueError](err); ok {
+		if _, ok := errors.AsType[*enumError](err); ok {
+			return invalidFlagValue(e.GetFlag().Name, err)
+		}
+	}
+
+	return err
+}
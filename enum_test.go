@@ -0,0 +1,226 @@
+package naistrix_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nais/naistrix"
+)
+
+type env string
+
+type enumFlags struct {
+	Environment naistrix.Enum[env] `name:"environment" usage:"The environment to deploy to."`
+	Level       string             `name:"level" enum:"debug,info,warn" usage:"Set the log level."`
+	Teams       []string           `name:"team" enum:"a,b,c"`
+}
+
+func newEnumApp(t *testing.T) (*naistrix.Application, *enumFlags, *bytes.Buffer) {
+	t.Helper()
+
+	var buf bytes.Buffer
+	app, _, err := naistrix.NewApplication("app", "title", "v0.0.0", naistrix.ApplicationWithWriter(&buf))
+	if err != nil {
+		t.Fatalf("unable to create application: %v", err)
+	}
+
+	flags := &enumFlags{
+		Environment: naistrix.NewEnum[env]("dev", "dev", "prod", "staging"),
+		Level:       "info",
+	}
+	err = app.AddCommand(&naistrix.Command{
+		Name:  "test",
+		Title: "Test command",
+		Flags: flags,
+		RunFunc: func(context.Context, *naistrix.Arguments, *naistrix.OutputWriter) error {
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("unable to add command: %v", err)
+	}
+
+	return app, flags, &buf
+}
+
+func TestEnumFlags(t *testing.T) {
+	tests := []struct {
+		name                string
+		args                []string
+		env                 map[string]string
+		config              string
+		expectedEnvironment env
+		expectedLevel       string
+		expectedTeams       []string
+		expectedErr         string
+	}{
+		{
+			name:                "defaults",
+			expectedEnvironment: "dev",
+			expectedLevel:       "info",
+		},
+		{
+			name:                "command line",
+			args:                []string{"--environment", "prod", "--level", "debug", "--team", "a,c"},
+			expectedEnvironment: "prod",
+			expectedLevel:       "debug",
+			expectedTeams:       []string{"a", "c"},
+		},
+		{
+			name:                "configuration file",
+			config:              "environment: staging\nlevel: warn\n",
+			expectedEnvironment: "staging",
+			expectedLevel:       "warn",
+		},
+		{
+			name:        "invalid enum value on the command line",
+			args:        []string{"--environment", "test"},
+			expectedErr: `Invalid value "test" for the --environment flag, must be one of: dev, prod, staging`,
+		},
+		{
+			name:        "invalid tag value on the command line",
+			args:        []string{"--level", "trace"},
+			expectedErr: `Invalid value "trace" for the --level flag, must be one of: debug, info, warn`,
+		},
+		{
+			name:        "invalid slice value on the command line",
+			args:        []string{"--team", "a,d"},
+			expectedErr: `Invalid value "d" for the --team flag, must be one of: a, b, c`,
+		},
+		{
+			name:        "invalid enum value in the environment",
+			env:         map[string]string{"APP_ENVIRONMENT": "test"},
+			expectedErr: `Invalid value "test" for the --environment flag, must be one of: dev, prod, staging`,
+		},
+		{
+			name:        "invalid tag value in the configuration file",
+			config:      "level: trace\n",
+			expectedErr: `Invalid value "trace" for the --level flag, must be one of: debug, info, warn`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+
+			configPath := filepath.Join(t.TempDir(), "config.yaml")
+			if err := os.WriteFile(configPath, []byte(tt.config), 0o600); err != nil {
+				t.Fatalf("unable to write config file: %v", err)
+			}
+
+			app, flags, _ := newEnumApp(t)
+			err := app.Run(naistrix.RunWithArgs(append([]string{"test", "--config", configPath}, tt.args...)))
+			if tt.expectedErr != "" {
+				if err == nil {
+					t.Fatalf("expected error to contain %q, got nil", tt.expectedErr)
+				}
+
+				e, ok := err.(naistrix.Error)
+				if !ok {
+					t.Fatalf("expected a naistrix.Error, got %T: %v", err, err)
+				}
+
+				if e.Message != tt.expectedErr {
+					t.Fatalf("expected error %q, got %q", tt.expectedErr, e.Message)
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if actual := flags.Environment.Value(); actual != tt.expectedEnvironment {
+				t.Fatalf("expected environment %q, got %q", tt.expectedEnvironment, actual)
+			}
+
+			if flags.Level != tt.expectedLevel {
+				t.Fatalf("expected level %q, got %q", tt.expectedLevel, flags.Level)
+			}
+
+			if strings.Join(flags.Teams, ",") != strings.Join(tt.expectedTeams, ",") {
+				t.Fatalf("expected teams %v, got %v", tt.expectedTeams, flags.Teams)
+			}
+		})
+	}
+}
+
+func TestEnumFlags_HelpAndCompletion(t *testing.T) {
+	run := func(args ...string) string {
+		t.Helper()
+
+		app, _, buf := newEnumApp(t)
+		if err := app.Run(naistrix.RunWithArgs(args)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return buf.String()
+	}
+
+	help := run("test", "-h", "--color=never")
+	for _, expected := range []string{
+		`The environment to deploy to. One of: dev, prod, staging. (default "dev")`,
+		"Set the log level. One of: debug, info, warn.",
+		"Teams. One of: a, b, c.",
+	} {
+		if !strings.Contains(help, expected) {
+			t.Errorf("expected help to contain %q, got: %s", expected, help)
+		}
+	}
+
+	for flag, expected := range map[string]string{
+		"--environment": "dev\nprod\nstaging\n:4\n",
+		"--level":       "debug\ninfo\nwarn\n:4\n",
+	} {
+		if completions := run("__complete", "test", flag, ""); completions != expected {
+			t.Errorf("expected completions for %s to be %q, got %q", flag, expected, completions)
+		}
+	}
+}
+
+func TestEnumFlags_Invalid(t *testing.T) {
+	tests := []struct {
+		name        string
+		flags       any
+		expectedErr string
+	}{
+		{
+			name: "enum tag on a non-string flag",
+			flags: &struct {
+				Count int `enum:"1,2"`
+			}{},
+			expectedErr: "the enum tag is only supported for string and []string flags",
+		},
+		{
+			name: "enum without allowed values",
+			flags: &struct {
+				Environment naistrix.Enum[env]
+			}{},
+			expectedErr: "enum flag without allowed values",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app, _, err := naistrix.NewApplication("app", "title", "v0.0.0")
+			if err != nil {
+				t.Fatalf("unable to create application: %v", err)
+			}
+
+			err = app.AddCommand(&naistrix.Command{
+				Name:  "test",
+				Title: "Test command",
+				Flags: tt.flags,
+				RunFunc: func(context.Context, *naistrix.Arguments, *naistrix.OutputWriter) error {
+					return nil
+				},
+			})
+			if err == nil || !strings.Contains(err.Error(), tt.expectedErr) {
+				t.Fatalf("expected error to contain %q, got: %v", tt.expectedErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/nais/naistrix"
+	"github.com/nais/naistrix/output"
+)
+
+type User struct {
+	Name  string `json:"name" yaml:"name"`
+	Email string `json:"email" yaml:"email"`
+}
+
+// names is a custom renderer that only outputs the names of the users.
+type names struct {
+	w io.Writer
+}
+
+func (n names) Render(v any) error {
+	for _, u := range v.([]User) {
+		if _, err := fmt.Fprintln(n.w, u.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func main() {
+	users := []User{
+		{Name: "Jane Doe", Email: "jane@example.com"},
+		{Name: "John Doe", Email: "john@example.com"},
+	}
+
+	app, _, err := naistrix.NewApplication(
+		"example",
+		"Example application with user selected output formats",
+		"v0.0.0",
+		naistrix.ApplicationWithOutputFormat("names", func(w io.Writer, _ string) (output.Renderer, error) {
+			return names{w: w}, nil
+		}),
+	)
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "error when creating application: %v\n", err)
+		os.Exit(1)
+	}
+
+	err = app.AddCommand(
+		&naistrix.Command{
+			Name:  "list",
+			Title: "List users in any of the registered formats.",
+			RunFunc: func(_ context.Context, _ *naistrix.Arguments, out *naistrix.OutputWriter) error {
+				return out.Render(users)
+			},
+		},
+		&naistrix.Command{
+			Name:          "export",
+			Title:         "Export users as JSON or YAML.",
+			OutputFormats: []naistrix.OutputFormat{naistrix.OutputFormatJSON, naistrix.OutputFormatYAML},
+			RunFunc: func(_ context.Context, _ *naistrix.Arguments, out *naistrix.OutputWriter) error {
+				return out.Render(users)
+			},
+		},
+	)
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "error when adding command: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := app.Run(); err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "error when running application: %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,14 @@
+package naistrix
+
+import "io"
+
+// SetTerminalSize overrides the terminal size detection and returns a function that restores the previous behaviour.
+// It is only available to tests.
+func SetTerminalSize(f func(w io.Writer) (width, height int)) (restore func()) {
+	prev := terminalSize
+	terminalSize = f
+	return func() { terminalSize = prev }
+}
+
+// UseColors reports whether colors are used for the provided color mode and writer. It is only available to tests.
+var UseColors = useColors
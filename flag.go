@@ -2,10 +2,13 @@ package naistrix
 
 import (
 	"context"
+	"encoding"
 	"fmt"
+	"net"
 	"reflect"
 	"regexp"
 	"slices"
+	"strconv"
 	"strings"
 	"time"
 
@@ -32,13 +35,43 @@ type GlobalFlags struct {
 	// VerboseLevel indicates the verbosity level of the application.
 	VerboseLevel Count `name:"verbose" short:"v" usage:"Set verbosity level. Use -v for verbose, -vv for debug, -vvv for trace."`
 
-	// NoColors can be used to disable colored output.
+	// Quiet can be used to only output data, suppressing informational, status and verbose output. Can not be combined
+	// with VerboseLevel.
+	Quiet bool `name:"quiet" usage:"Only output data, suppressing informational and status messages."`
+
+	// Output is the output format used when rendering data with [OutputWriter.Render].
+	Output string `name:"output" usage:"Set the output |format|."`
+
+	// Columns are the headings of the table columns to render, in the order they should be rendered. Columns, Sort and
+	// Filter apply to the table, wide, csv and tsv output formats.
+	Columns []string `name:"columns" usage:"Comma-separated list of |columns| to show in tables and csv or tsv output."`
+
+	// Sort are the headings of the table columns used to sort the rows. A "-" prefix sorts in descending order.
+	Sort []string `name:"sort" usage:"Sort rows by |columns|. Prefix a column with - to sort in descending order."`
+
+	// Filter are filters on the form "column=value" or "column!=value" used to select the table rows to render.
+	Filter []string `name:"filter" usage:"Only show rows matching |column=value|. Use != to exclude rows. Can be repeated."`
+
+	// NoPager can be used to disable the pager used for long output, see [ApplicationWithPager].
+	NoPager bool `name:"no-pager" usage:"Do not show long output in a pager."`
+
+	// Color controls when colors are used in the output. See [ColorMode] for the available modes.
+	Color ColorMode `name:"color" usage:"Set when to use colors in the output. One of: auto, always, never."`
+
+	// NoColors is set when colors are disabled using --color=never, or the hidden --no-colors flag that it replaces.
+	//
+	// Deprecated: Use Color instead.
 	NoColors bool `name:"no-colors" usage:"Disable colors in the output."`
 
 	// Config is the location of the configuration file.
 	Config string `name:"config" usage:"Specify the |path| to the configuration file."`
 }
 
+// IsQuiet checks if the application is running in quiet mode (-q).
+func (f GlobalFlags) IsQuiet() bool {
+	return f.Quiet
+}
+
 // IsVerbose checks if the application is running in verbose mode (-v).
 func (f GlobalFlags) IsVerbose() bool {
 	return f.VerboseLevel > OutputVerbosityLevelNormal
@@ -65,12 +98,33 @@ type FlagAutoCompleter interface {
 	AutoComplete(ctx context.Context, args *Arguments, toComplete string, flags any) (completions []string, activeHelp string)
 }
 
+// FlagValue can be implemented by the types of flag fields to control how values are parsed, for types that are not
+// supported out of the box. It is compatible with [pflag.Value], so types implementing that interface can be used as
+// is. Fields of types implementing [encoding.TextUnmarshaler] can be used as flags as well.
+//
+// Values from the configuration file and environment variables are parsed using Set, just like values from the command
+// line, so validation done in Set applies to all sources. Values set on the command line are only parsed once, so types
+// that accumulate the values passed to Set, like a flag that can be repeated, work as expected.
+type FlagValue interface {
+	// String returns the current value, which is also shown as the default value in help output.
+	String() string
+
+	// Set parses and sets the value. The returned error is shown to the end-user.
+	Set(value string) error
+
+	// Type returns the name of the type of the value, which is shown in help output.
+	Type() string
+}
+
 // FileAutoCompleter is an interface that can be implemented by flag values to provide auto-completion functionality for
 // a set of file extensions.
 type FileAutoCompleter interface {
 	FileExtensions() (extensions []string)
 }
 
+// setupFlag adds a flag to the flag set, bound to value, which must be a pointer to one of the supported flag types:
+// string, bool, int, int64, uint, uint64, float64, []string, []int, map[string]string, [time.Duration], [time.Time],
+// [net.IP], *[url.URL], [ByteSize] or [Count], or to a type implementing [FlagValue] or [encoding.TextUnmarshaler].
 func setupFlag(name, short, usage string, value any, flags *pflag.FlagSet) error {
 	if len(short) > 1 {
 		return fmt.Errorf("short flag must be a single character")
@@ -125,8 +179,49 @@ func setupFlag(name, short, usage string, value any, flags *pflag.FlagSet) error
 		} else {
 			flags.CountVarP(intPtr, name, short, usage)
 		}
+	case *int64:
+		if short == "" {
+			flags.Int64Var(ptr, name, *ptr, usage)
+		} else {
+			flags.Int64VarP(ptr, name, short, *ptr, usage)
+		}
+	case *uint64:
+		if short == "" {
+			flags.Uint64Var(ptr, name, *ptr, usage)
+		} else {
+			flags.Uint64VarP(ptr, name, short, *ptr, usage)
+		}
+	case *float64:
+		if short == "" {
+			flags.Float64Var(ptr, name, *ptr, usage)
+		} else {
+			flags.Float64VarP(ptr, name, short, *ptr, usage)
+		}
+	case *[]int:
+		if short == "" {
+			flags.IntSliceVar(ptr, name, *ptr, usage)
+		} else {
+			flags.IntSliceVarP(ptr, name, short, *ptr, usage)
+		}
+	case *map[string]string:
+		if short == "" {
+			flags.StringToStringVar(ptr, name, *ptr, usage)
+		} else {
+			flags.StringToStringVarP(ptr, name, short, *ptr, usage)
+		}
+	case *net.IP:
+		if short == "" {
+			flags.IPVar(ptr, name, *ptr, usage)
+		} else {
+			flags.IPVarP(ptr, name, short, *ptr, usage)
+		}
 	default:
-		return fmt.Errorf("unknown flag type: %T", value)
+		v, ok := flagValue(value)
+		if !ok {
+			return fmt.Errorf("unknown flag type: %T", value)
+		}
+
+		flags.VarP(v, name, short, usage)
 	}
 
 	return nil
@@ -149,6 +244,7 @@ func setupFlags(cmd *cobra.Command, inputArgs []Argument, flags any, flagSet *pf
 		})
 	}
 
+	var constraints flagConstraints
 	fields := reflect.TypeOf(flags).Elem()
 	values := reflect.ValueOf(flags).Elem()
 	for i := range fields.NumField() {
@@ -172,6 +268,21 @@ func setupFlags(cmd *cobra.Command, inputArgs []Argument, flags any, flagSet *pf
 			return fmt.Errorf("failed to setup flag %q: %w", flagName, err)
 		}
 
+		_, isFlagAutoCompleter := actualValue.(FlagAutoCompleter)
+		_, isFileAutoCompleter := actualValue.(FileAutoCompleter)
+		autoComplete := !isFlagAutoCompleter && !isFileAutoCompleter
+		if err := setupEnumFlag(cmd, field, flagSet.Lookup(flagName), autoComplete); err != nil {
+			return fmt.Errorf("failed to setup flag %q: %w", flagName, err)
+		}
+
+		if _, err := getFlagValidators(field); err != nil {
+			return fmt.Errorf("failed to setup flag %q: %w", flagName, err)
+		}
+
+		if err := constraints.add(field, flagName); err != nil {
+			return fmt.Errorf("failed to setup flag %q: %w", flagName, err)
+		}
+
 		switch v := actualValue.(type) {
 		case FlagAutoCompleter:
 			err := cmd.RegisterFlagCompletionFunc(
@@ -207,6 +318,10 @@ func setupFlags(cmd *cobra.Command, inputArgs []Argument, flags any, flagSet *pf
 		}
 	}
 
+	if err := constraints.apply(cmd, flagSet); err != nil {
+		return fmt.Errorf("invalid flag constraints: %w", err)
+	}
+
 	return nil
 }
 
@@ -218,12 +333,26 @@ func setupFlags(cmd *cobra.Command, inputArgs []Argument, flags any, flagSet *pf
 // *[]string => *[]string
 // *MyStringType => *string
 // *[]MyStringType => *[]string
+// *MyMapType => *map[string]string
+//
+// Types implementing [FlagValue] or [encoding.TextUnmarshaler] are never converted, so their values are parsed using
+// the interface.
 func unwrap(value any) any {
+	switch value.(type) {
+	case FlagValue, encoding.TextUnmarshaler:
+		return value
+	}
+
 	v := reflect.ValueOf(value)
 
 	switch v.Elem().Kind() {
 	case reflect.String:
 		return v.Convert(reflect.TypeFor[*string]()).Interface()
+	case reflect.Map:
+		if v.CanConvert(reflect.TypeFor[*map[string]string]()) {
+			return v.Convert(reflect.TypeFor[*map[string]string]()).Interface()
+		}
+		return value
 	case reflect.Slice:
 		switch v.Elem().Type().Elem().Kind() {
 		case reflect.String:
@@ -260,8 +389,12 @@ func validateFlags(flags any) error {
 
 // syncViperToFlags syncs values from Viper back to the flags struct.
 // This ensures that values from config files and environment variables
-// are reflected in the flags struct, not just CLI flag values.
-func syncViperToFlags(flags any, config *viper.Viper) error {
+// are reflected in the flags struct, not just CLI flag values. Fields
+// bound directly to flags in flagSet that are set on the command line
+// are left as parsed by pflag, and so are flags with a configuration
+// default overridden by an exclusive flag, see [overriddenFlag]. Values
+// that can not be parsed are reported to the end-user as an [Error].
+func syncViperToFlags(flags any, flagSet *pflag.FlagSet, config *viper.Viper) error {
 	if flags == nil {
 		return nil
 	}
@@ -291,19 +424,63 @@ func syncViperToFlags(flags any, config *viper.Viper) error {
 			continue
 		}
 
-		setValue(value, flagName, config)
+		// defaults from the configuration file are left out when overridden by an exclusive flag
+		f := flagSet.Lookup(flagName)
+		if f != nil && overriddenFlag(flagSet, f, config) {
+			continue
+		}
+
+		// values set on the command line have already been parsed into fields that are bound directly to the flag, and
+		// setting them again would duplicate the values of types that accumulate values, see [unwrap]
+		ptr := value.Addr().Interface()
+		if f == nil || !f.Changed || unwrap(ptr) != ptr {
+			if err := setValue(value, flagName, config); err != nil {
+				return invalidFlagValue(flagName, err)
+			}
+		}
+
+		if err := syncEnumFlag(field, value); err != nil {
+			return invalidFlagValue(flagName, err)
+		}
 	}
 
 	return nil
 }
 
-// setValue sets a value from Viper into the provided reflect.Value based on its kind.
-func setValue(v reflect.Value, configKey string, config *viper.Viper) {
+// setValue sets a value from Viper into the provided reflect.Value based on its type. Values that are parsed from
+// strings, like IP addresses and URLs, are parsed the same way as on the command line.
+func setValue(v reflect.Value, configKey string, config *viper.Viper) error {
+	switch v.Type() {
+	case reflect.TypeFor[time.Duration]():
+		v.Set(reflect.ValueOf(config.GetDuration(configKey)))
+		return nil
+	case reflect.TypeFor[net.IP]():
+		s := config.GetString(configKey)
+		ip := net.ParseIP(strings.TrimSpace(s))
+		if ip == nil {
+			return fmt.Errorf("invalid IP address %q", s)
+		}
+
+		v.Set(reflect.ValueOf(ip))
+		return nil
+	case reflect.TypeFor[time.Time]():
+		// timestamps in configuration files are already parsed
+		if t, ok := config.Get(configKey).(time.Time); ok {
+			v.Set(reflect.ValueOf(t))
+			return nil
+		}
+	}
+
+	if fv, ok := flagValue(v.Addr().Interface()); ok {
+		return fv.Set(config.GetString(configKey))
+	}
+
 	switch v.Kind() {
 	case reflect.String:
 		v.SetString(config.GetString(configKey))
 	case reflect.Slice:
-		if v.Type().Elem().Kind() == reflect.String {
+		switch v.Type().Elem().Kind() {
+		case reflect.String:
 			stringSlice := config.GetStringSlice(configKey)
 			newSlice := reflect.MakeSlice(v.Type(), len(stringSlice), len(stringSlice))
 			for i, s := range stringSlice {
@@ -311,20 +488,95 @@ func setValue(v reflect.Value, configKey string, config *viper.Viper) {
 			}
 
 			v.Set(newSlice)
+		case reflect.Int:
+			ints, err := configIntSlice(config.Get(configKey))
+			if err != nil {
+				return err
+			}
+
+			v.Set(reflect.ValueOf(ints).Convert(v.Type()))
+		}
+	case reflect.Map:
+		if v.Type().Key().Kind() == reflect.String && v.Type().Elem().Kind() == reflect.String {
+			m, err := configStringMap(config.Get(configKey))
+			if err != nil {
+				return err
+			}
+
+			v.Set(reflect.ValueOf(m).Convert(v.Type()))
 		}
 	case reflect.Bool:
 		v.SetBool(config.GetBool(configKey))
 	case reflect.Int, reflect.Int64:
-		if v.Type() == reflect.TypeFor[time.Duration]() {
-			v.Set(reflect.ValueOf(config.GetDuration(configKey)))
-		} else {
-			v.SetInt(int64(config.GetInt(configKey)))
+		v.SetInt(config.GetInt64(configKey))
+	case reflect.Uint, reflect.Uint64:
+		v.SetUint(config.GetUint64(configKey))
+	case reflect.Float64:
+		v.SetFloat(config.GetFloat64(configKey))
+	}
+
+	return nil
+}
+
+// configIntSlice converts a value from Viper to a slice of integers. Strings, like values from environment variables,
+// are split on commas.
+func configIntSlice(value any) ([]int, error) {
+	var values []any
+	switch v := value.(type) {
+	case string:
+		for s := range strings.SplitSeq(v, ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				values = append(values, s)
+			}
+		}
+	case []int:
+		return v, nil
+	case []any:
+		values = v
+	default:
+		values = []any{v}
+	}
+
+	ret := make([]int, 0, len(values))
+	for _, val := range values {
+		i, err := strconv.Atoi(strings.TrimSpace(fmt.Sprint(val)))
+		if err != nil {
+			return nil, fmt.Errorf("invalid integer %q", fmt.Sprint(val))
+		}
+		ret = append(ret, i)
+	}
+
+	return ret, nil
+}
+
+// configStringMap converts a value from Viper to a map of strings. Strings, like values from environment variables,
+// are parsed as comma-separated key=value pairs.
+func configStringMap(value any) (map[string]string, error) {
+	ret := make(map[string]string)
+	switch v := value.(type) {
+	case string:
+		for pair := range strings.SplitSeq(v, ",") {
+			if pair = strings.TrimSpace(pair); pair == "" {
+				continue
+			}
+
+			key, val, ok := strings.Cut(pair, "=")
+			if !ok {
+				return nil, fmt.Errorf("invalid key=value pair %q", pair)
+			}
+			ret[strings.TrimSpace(key)] = val
 		}
-	case reflect.Uint:
-		v.SetUint(uint64(config.GetUint(configKey)))
+	case map[string]any:
+		for key, val := range v {
+			ret[key] = fmt.Sprint(val)
+		}
+	case map[string]string:
+		return v, nil
 	default:
-		return
+		return nil, fmt.Errorf("expected key=value pairs, got %v", value)
 	}
+
+	return ret, nil
 }
 
 // getFlagName retrieves the flag name from the struct field tag or defaults to the lowercased field name.
@@ -353,3 +605,29 @@ func getFlagShort(field reflect.StructField) string {
 	}
 	return s
 }
+
+// getTagValues retrieves the comma-separated values of the struct field tag, or returns nil if not set.
+func getTagValues(field reflect.StructField, key string) []string {
+	tag, ok := field.Tag.Lookup(key)
+	if !ok {
+		return nil
+	}
+
+	ret := make([]string, 0)
+	for v := range strings.SplitSeq(tag, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			ret = append(ret, v)
+		}
+	}
+	return ret
+}
+
+// appendFlagUsage appends the text as a separate sentence to the usage of the flag, which is shown in the help output
+// and the generated documentation.
+func appendFlagUsage(flag *pflag.Flag, text string) {
+	usage := strings.TrimSpace(flag.Usage)
+	if usage != "" && !strings.HasSuffix(usage, ".") {
+		usage += "."
+	}
+	flag.Usage = strings.TrimSpace(usage + " " + text)
+}
@@ -0,0 +1,286 @@
+package naistrix
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// Annotations set on flags with constraints. The flag groups are stored as the names of the flags in the group,
+// separated by spaces. The constraints are validated by [validateFlagConstraints]. The exclusive and oneof groups are
+// also marked using cobra, so they are known to shell completions, but cobra does not export the annotations it uses.
+// Cobra has no constraint like requires, as the flags marked using [cobra.Command.MarkFlagsRequiredTogether] require
+// each other.
+const (
+	exclusiveFlagsAnnotation   = "naistrix_annotation_exclusive"
+	oneRequiredFlagsAnnotation = "naistrix_annotation_one_required"
+	requiresFlagsAnnotation    = "naistrix_annotation_requires"
+)
+
+// flagConstraints holds the constraints set on the flags of a flags struct using the `required`, `exclusive`, `oneof`
+// and `requires` struct tags.
+type flagConstraints struct {
+	required  []string
+	exclusive flagGroups
+	oneOf     flagGroups
+	requires  []flagRequirement
+}
+
+// flagGroups holds named groups of flags, in the order the groups were declared.
+type flagGroups struct {
+	names []string
+	flags map[string][]string
+}
+
+// flagRequirement holds the flags that must be set when a flag is set.
+type flagRequirement struct {
+	flag     string
+	requires []string
+}
+
+// add adds the flag to the group.
+func (g *flagGroups) add(group, flag string) {
+	if g.flags == nil {
+		g.flags = make(map[string][]string)
+	}
+
+	if _, ok := g.flags[group]; !ok {
+		g.names = append(g.names, group)
+	}
+	g.flags[group] = append(g.flags[group], flag)
+}
+
+// add adds the constraints set using struct tags on the field of a flag.
+func (c *flagConstraints) add(field reflect.StructField, flagName string) error {
+	if tag, ok := field.Tag.Lookup("required"); ok {
+		required, err := strconv.ParseBool(tag)
+		if err != nil {
+			return fmt.Errorf("invalid value for the required tag: %q", tag)
+		}
+
+		if required {
+			c.required = append(c.required, flagName)
+		}
+	}
+
+	for _, group := range getTagValues(field, "exclusive") {
+		c.exclusive.add(group, flagName)
+	}
+
+	for _, group := range getTagValues(field, "oneof") {
+		c.oneOf.add(group, flagName)
+	}
+
+	if requires := getTagValues(field, "requires"); len(requires) > 0 {
+		c.requires = append(c.requires, flagRequirement{flag: flagName, requires: requires})
+	}
+
+	return nil
+}
+
+// apply marks the flags in the flag set of the command according to the constraints, and adds the constraints to the
+// usage of the flags. Required flags and flag groups are marked using cobra as well, so they are known to shell
+// completions. Groups can only contain flags from the same flags struct.
+func (c *flagConstraints) apply(cmd *cobra.Command, flagSet *pflag.FlagSet) error {
+	for _, name := range c.required {
+		if err := cobra.MarkFlagRequired(flagSet, name); err != nil {
+			return fmt.Errorf("failed to mark flag %q as required: %w", name, err)
+		}
+		appendFlagUsage(flagSet.Lookup(name), "Required.")
+	}
+
+	for _, group := range c.exclusive.names {
+		names := c.exclusive.flags[group]
+		if len(names) < 2 {
+			return fmt.Errorf("the exclusive group %q must contain at least two flags", group)
+		}
+
+		if err := addFlagGroup(flagSet, exclusiveFlagsAnnotation, names); err != nil {
+			return err
+		}
+		cmd.MarkFlagsMutuallyExclusive(names...)
+		for _, name := range names {
+			appendFlagUsage(flagSet.Lookup(name), "Can not be used with "+joinFlagNames(others(names, name), "or")+".")
+		}
+	}
+
+	for _, group := range c.oneOf.names {
+		names := c.oneOf.flags[group]
+		if len(names) < 2 {
+			return fmt.Errorf("the oneof group %q must contain at least two flags", group)
+		}
+
+		if err := addFlagGroup(flagSet, oneRequiredFlagsAnnotation, names); err != nil {
+			return err
+		}
+		cmd.MarkFlagsOneRequired(names...)
+		for _, name := range names {
+			appendFlagUsage(flagSet.Lookup(name), "Required unless "+joinFlagNames(others(names, name), "or")+" is set.")
+		}
+	}
+
+	for _, r := range c.requires {
+		for _, name := range r.requires {
+			if flagSet.Lookup(name) == nil {
+				return fmt.Errorf("flag %q requires unknown flag %q", r.flag, name)
+			}
+		}
+
+		if err := flagSet.SetAnnotation(r.flag, requiresFlagsAnnotation, r.requires); err != nil {
+			return fmt.Errorf("failed to mark flag %q as requiring other flags: %w", r.flag, err)
+		}
+		appendFlagUsage(flagSet.Lookup(r.flag), "Requires "+joinFlagNames(r.requires, "and")+".")
+	}
+
+	return nil
+}
+
+// addFlagGroup adds the group of flags to the annotation of each flag in the group.
+func addFlagGroup(flagSet *pflag.FlagSet, annotation string, names []string) error {
+	group := strings.Join(names, " ")
+	for _, name := range names {
+		groups := append(slices.Clone(flagSet.Lookup(name).Annotations[annotation]), group)
+		if err := flagSet.SetAnnotation(name, annotation, groups); err != nil {
+			return fmt.Errorf("failed to add flag %q to group %q: %w", name, group, err)
+		}
+	}
+	return nil
+}
+
+// validateFlagConstraints validates the constraints set using the `required`, `exclusive`, `oneof` and `requires`
+// struct tags on the flags of the executed command, and returns an [Error] for the first constraint that is not met.
+// Flags are considered set when set on the command line, in the configuration file or using environment variables.
+//
+// Values in the configuration file are defaults, so a flag in an exclusive group that is only set in the configuration
+// file is ignored when another flag in the group is set on the command line or using an environment variable, see
+// [overriddenFlag]. Only flags set on the command line or using environment variables can therefore conflict.
+//
+// Cobra validates the required flags and flag groups after the run hooks as well, but only considers flags set on the
+// command line. Required flags, and flags in oneof groups, that are only set in the configuration or using environment
+// variables are therefore marked as changed, so the values must be synced to the flags structs before the constraints
+// are validated. Overridden flags are not considered set, and are not marked, so they do not conflict in cobra either.
+func validateFlagConstraints(flags *pflag.FlagSet, config *viper.Viper) error {
+	isSet := func(name string) bool {
+		f := flags.Lookup(name)
+		return f != nil && (f.Changed || config.IsSet(name)) && !overriddenFlag(flags, f, config)
+	}
+
+	var missing []string
+	var exclusive, oneRequired []string
+	flags.VisitAll(func(f *pflag.Flag) {
+		if slices.Contains(f.Annotations[cobra.BashCompOneRequiredFlag], "true") && !isSet(f.Name) {
+			missing = append(missing, f.Name)
+		}
+
+		for _, group := range f.Annotations[exclusiveFlagsAnnotation] {
+			if !slices.Contains(exclusive, group) {
+				exclusive = append(exclusive, group)
+			}
+		}
+
+		for _, group := range f.Annotations[oneRequiredFlagsAnnotation] {
+			if !slices.Contains(oneRequired, group) {
+				oneRequired = append(oneRequired, group)
+			}
+		}
+	})
+
+	switch len(missing) {
+	case 0:
+	case 1:
+		return Errorf("The %s flag is required", joinFlagNames(missing, "and"))
+	default:
+		return Errorf("The %s flags are required", joinFlagNames(missing, "and"))
+	}
+
+	for _, group := range exclusive {
+		names := strings.Fields(group)
+		if set := slices.DeleteFunc(names, func(name string) bool { return !isSet(name) }); len(set) > 1 {
+			return Errorf("The %s flags can not be used together", joinFlagNames(set, "and"))
+		}
+	}
+
+	for _, group := range oneRequired {
+		names := strings.Fields(group)
+		if !slices.ContainsFunc(names, isSet) {
+			return Errorf("At least one of the %s flags is required", joinFlagNames(names, "or"))
+		}
+	}
+
+	var err error
+	flags.VisitAll(func(f *pflag.Flag) {
+		if err != nil || !isSet(f.Name) {
+			return
+		}
+
+		for _, name := range f.Annotations[requiresFlagsAnnotation] {
+			if !isSet(name) {
+				err = Errorf("The --%s flag requires the --%s flag", f.Name, name)
+				return
+			}
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	flags.VisitAll(func(f *pflag.Flag) {
+		required := slices.Contains(f.Annotations[cobra.BashCompOneRequiredFlag], "true")
+		if !f.Changed && isSet(f.Name) && (required || len(f.Annotations[oneRequiredFlagsAnnotation]) > 0) {
+			f.Changed = true
+		}
+	})
+
+	return nil
+}
+
+// explicitlySet checks if the flag is set on the command line or using an environment variable, as opposed to only
+// being set in the configuration file.
+func explicitlySet(f *pflag.Flag, config *viper.Viper) bool {
+	return f.Changed || (config.IsSet(f.Name) && !config.InConfig(f.Name))
+}
+
+// overriddenFlag checks if the flag is only set in the configuration file, while another flag in one of its exclusive
+// groups is set on the command line or using an environment variable. The value of an overridden flag is not synced
+// to the flags struct, and the flag is considered not set when validating the constraints.
+func overriddenFlag(flags *pflag.FlagSet, f *pflag.Flag, config *viper.Viper) bool {
+	if explicitlySet(f, config) {
+		return false
+	}
+
+	for _, group := range f.Annotations[exclusiveFlagsAnnotation] {
+		for _, name := range strings.Fields(group) {
+			if other := flags.Lookup(name); name != f.Name && other != nil && explicitlySet(other, config) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// others returns the names except name.
+func others(names []string, name string) []string {
+	return slices.DeleteFunc(slices.Clone(names), func(n string) bool { return n == name })
+}
+
+// joinFlagNames joins the flag names for use in messages, like "--a", "--a and --b" or "--a, --b or --c", depending on
+// the conjunction.
+func joinFlagNames(names []string, conjunction string) string {
+	flags := make([]string, len(names))
+	for i, name := range names {
+		flags[i] = "--" + name
+	}
+
+	if len(flags) < 2 {
+		return strings.Join(flags, "")
+	}
+
+	return strings.Join(flags[:len(flags)-1], ", ") + " " + conjunction + " " + flags[len(flags)-1]
+}
@@ -0,0 +1,241 @@
+package naistrix_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nais/naistrix"
+)
+
+type deployFlags struct {
+	Team      string `name:"team" required:"true" usage:"The team owning the application."`
+	Image     string `name:"image" exclusive:"source" oneof:"source"`
+	Directory string `name:"directory" exclusive:"source" oneof:"source" usage:"Build the image from |directory|."`
+	Tag       string `name:"tag" requires:"image" usage:"The image tag"`
+}
+
+func newDeployApp(t *testing.T, flags *deployFlags, validated *bool) (*naistrix.Application, *bytes.Buffer) {
+	t.Helper()
+
+	var buf bytes.Buffer
+	app, _, err := naistrix.NewApplication("app", "title", "v0.0.0", naistrix.ApplicationWithWriter(&buf))
+	if err != nil {
+		t.Fatalf("unable to create application: %v", err)
+	}
+
+	err = app.AddCommand(&naistrix.Command{
+		Name:  "deploy",
+		Title: "Deploy an application",
+		Flags: flags,
+		ValidateFunc: func(context.Context, *naistrix.Arguments) error {
+			*validated = true
+			return nil
+		},
+		RunFunc: func(context.Context, *naistrix.Arguments, *naistrix.OutputWriter) error {
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("unable to add command: %v", err)
+	}
+
+	return app, &buf
+}
+
+func TestFlagConstraints(t *testing.T) {
+	tests := []struct {
+		name              string
+		args              []string
+		env               map[string]string
+		config            string
+		expectedDirectory string
+		expectedErr       string
+	}{
+		{
+			name: "all constraints met",
+			args: []string{"--team", "nais", "--image", "nginx", "--tag", "latest"},
+		},
+		{
+			name:              "flags set in the configuration file and the environment",
+			env:               map[string]string{"APP_TEAM": "nais"},
+			config:            "directory: ./app\n",
+			expectedDirectory: "./app",
+		},
+		{
+			name:        "missing required flag",
+			args:        []string{"--image", "nginx"},
+			expectedErr: "The --team flag is required",
+		},
+		{
+			name:        "exclusive flags",
+			args:        []string{"--team", "nais", "--image", "nginx", "--directory", "./app"},
+			expectedErr: "The --image and --directory flags can not be used together",
+		},
+		{
+			name:   "exclusive flag set in the configuration file is overridden on the command line",
+			args:   []string{"--team", "nais", "--image", "nginx", "--tag", "latest"},
+			config: "directory: ./app\n",
+		},
+		{
+			name:        "exclusive flags set in the environment",
+			args:        []string{"--team", "nais", "--image", "nginx"},
+			env:         map[string]string{"APP_DIRECTORY": "./app"},
+			expectedErr: "The --image and --directory flags can not be used together",
+		},
+		{
+			name:        "none of the one of flags",
+			args:        []string{"--team", "nais"},
+			expectedErr: "At least one of the --image or --directory flags is required",
+		},
+		{
+			name:        "missing required flag of another flag",
+			args:        []string{"--team", "nais", "--directory", "./app", "--tag", "latest"},
+			expectedErr: "The --tag flag requires the --image flag",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+
+			configPath := filepath.Join(t.TempDir(), "config.yaml")
+			if err := os.WriteFile(configPath, []byte(tt.config), 0o600); err != nil {
+				t.Fatalf("unable to write config file: %v", err)
+			}
+
+			flags := &deployFlags{}
+			validated := false
+			app, _ := newDeployApp(t, flags, &validated)
+			err := app.Run(naistrix.RunWithArgs(append([]string{"deploy", "--config", configPath}, tt.args...)))
+			if tt.expectedErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+
+				if !validated {
+					t.Fatalf("expected the validate function to be called")
+				}
+
+				if flags.Directory != tt.expectedDirectory {
+					t.Fatalf("expected directory %q, got %q", tt.expectedDirectory, flags.Directory)
+				}
+				return
+			}
+
+			e, ok := err.(naistrix.Error)
+			if !ok {
+				t.Fatalf("expected a naistrix.Error, got %T: %v", err, err)
+			}
+
+			if e.Message != tt.expectedErr {
+				t.Fatalf("expected error %q, got %q", tt.expectedErr, e.Message)
+			}
+
+			if validated {
+				t.Fatalf("expected the validate function not to be called")
+			}
+		})
+	}
+}
+
+func TestFlagConstraints_Help(t *testing.T) {
+	validated := false
+	app, buf := newDeployApp(t, &deployFlags{}, &validated)
+	if err := app.Run(naistrix.RunWithArgs([]string{"deploy", "-h"})); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	help := buf.String()
+	for _, expected := range []string{
+		"The team owning the application. Required.",
+		"Image. Can not be used with --directory. Required unless --directory is set.",
+		"Build the image from DIRECTORY. Can not be used with --image. Required unless --image is set.",
+		"The image tag. Requires --image.",
+	} {
+		if !strings.Contains(help, expected) {
+			t.Errorf("expected help to contain %q, got: %s", expected, help)
+		}
+	}
+}
+
+func TestFlagConstraints_Completion(t *testing.T) {
+	validated := false
+	app, buf := newDeployApp(t, &deployFlags{}, &validated)
+	args := []string{"__complete", "deploy", "--team", "nais", "--image", "nginx", "--"}
+	if err := app.Run(naistrix.RunWithArgs(args)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	completions := buf.String()
+	if !strings.Contains(completions, "--tag") {
+		t.Errorf("expected completions to contain --tag, got: %s", completions)
+	}
+
+	if strings.Contains(completions, "--directory") {
+		t.Errorf("expected completions not to contain the exclusive --directory flag, got: %s", completions)
+	}
+}
+
+func TestFlagConstraints_Invalid(t *testing.T) {
+	tests := []struct {
+		name        string
+		flags       any
+		expectedErr string
+	}{
+		{
+			name: "invalid required value",
+			flags: &struct {
+				Team string `required:"yes"`
+			}{},
+			expectedErr: `invalid value for the required tag: "yes"`,
+		},
+		{
+			name: "exclusive group with a single flag",
+			flags: &struct {
+				Image string `exclusive:"source"`
+			}{},
+			expectedErr: `the exclusive group "source" must contain at least two flags`,
+		},
+		{
+			name: "oneof group with a single flag",
+			flags: &struct {
+				Image string `oneof:"source"`
+			}{},
+			expectedErr: `the oneof group "source" must contain at least two flags`,
+		},
+		{
+			name: "unknown required flag",
+			flags: &struct {
+				Tag string `requires:"image"`
+			}{},
+			expectedErr: `flag "tag" requires unknown flag "image"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app, _, err := naistrix.NewApplication("app", "title", "v0.0.0")
+			if err != nil {
+				t.Fatalf("unable to create application: %v", err)
+			}
+
+			err = app.AddCommand(&naistrix.Command{
+				Name:  "test",
+				Title: "Test command",
+				Flags: tt.flags,
+				RunFunc: func(context.Context, *naistrix.Arguments, *naistrix.OutputWriter) error {
+					return nil
+				},
+			})
+			if err == nil || !strings.Contains(err.Error(), tt.expectedErr) {
+				t.Fatalf("expected error to contain %q, got: %v", tt.expectedErr, err)
+			}
+		})
+	}
+}
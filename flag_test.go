@@ -1,8 +1,20 @@
 package naistrix_test
 
 import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/nais/naistrix"
 )
@@ -59,7 +71,7 @@ func TestSetupFlag(t *testing.T) {
 		}
 
 		flags := &struct {
-			Flag map[string]string
+			Flag map[string]int
 		}{}
 
 		if err := app.AddGlobalFlags(flags); err == nil {
@@ -86,3 +98,402 @@ func TestSetupFlag(t *testing.T) {
 		}
 	})
 }
+
+type flagTypes struct {
+	Ratio    float64           `name:"ratio"`
+	Offset   int64             `name:"offset"`
+	Limit    uint64            `name:"limit"`
+	Ports    []int             `name:"ports"`
+	Labels   map[string]string `name:"label"`
+	IP       net.IP            `name:"ip"`
+	Endpoint *url.URL          `name:"endpoint"`
+	Since    time.Time         `name:"since"`
+	Size     naistrix.ByteSize `name:"size"`
+}
+
+func TestFlagTypes(t *testing.T) {
+	expected := flagTypes{
+		Ratio:    0.5,
+		Offset:   -10,
+		Limit:    100,
+		Ports:    []int{80, 443},
+		Labels:   map[string]string{"team": "nais", "env": "dev"},
+		IP:       net.ParseIP("10.0.0.1"),
+		Endpoint: &url.URL{Scheme: "https", Host: "example.com", Path: "/api"},
+		Since:    time.Date(2024, 5, 17, 12, 0, 0, 0, time.UTC),
+		Size:     1536 * 1024 * 1024,
+	}
+
+	tests := []struct {
+		name        string
+		args        []string
+		env         map[string]string
+		config      string
+		expectedErr string
+	}{
+		{
+			name: "command line",
+			args: []string{
+				"--ratio", "0.5",
+				"--offset", "-10",
+				"--limit", "100",
+				"--ports", "80,443",
+				"--label", "team=nais", "--label", "env=dev",
+				"--ip", "10.0.0.1",
+				"--endpoint", "https://example.com/api",
+				"--since", "2024-05-17T12:00:00Z",
+				"--size", "1.5GiB",
+			},
+		},
+		{
+			name: "environment",
+			env: map[string]string{
+				"APP_RATIO":    "0.5",
+				"APP_OFFSET":   "-10",
+				"APP_LIMIT":    "100",
+				"APP_PORTS":    "80,443",
+				"APP_LABEL":    "team=nais,env=dev",
+				"APP_IP":       "10.0.0.1",
+				"APP_ENDPOINT": "https://example.com/api",
+				"APP_SINCE":    "2024-05-17T12:00:00Z",
+				"APP_SIZE":     "1536Mi",
+			},
+		},
+		{
+			name: "configuration file",
+			config: "ratio: 0.5\n" +
+				"offset: -10\n" +
+				"limit: 100\n" +
+				"ports: [80, 443]\n" +
+				"label:\n  team: nais\n  env: dev\n" +
+				"ip: 10.0.0.1\n" +
+				"endpoint: https://example.com/api\n" +
+				"since: 2024-05-17T12:00:00Z\n" +
+				"size: 1.5GiB\n",
+		},
+		{
+			name:        "invalid value on the command line",
+			args:        []string{"--endpoint", "example.com"},
+			expectedErr: "must be an absolute URL",
+		},
+		{
+			name:        "invalid value in the environment",
+			env:         map[string]string{"APP_IP": "not-an-ip"},
+			expectedErr: `Invalid value for the --ip flag: invalid IP address "not-an-ip"`,
+		},
+		{
+			name:        "invalid value in the configuration file",
+			config:      "size: 10 parsecs\n",
+			expectedErr: `Invalid value for the --size flag: invalid size "10 parsecs"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+
+			configPath := filepath.Join(t.TempDir(), "config.yaml")
+			if err := os.WriteFile(configPath, []byte(tt.config), 0o600); err != nil {
+				t.Fatalf("unable to write config file: %v", err)
+			}
+
+			app, _, err := naistrix.NewApplication("app", "title", "v0.0.0", naistrix.ApplicationWithWriter(io.Discard))
+			if err != nil {
+				t.Fatalf("unable to create application: %v", err)
+			}
+
+			flags := &flagTypes{}
+			err = app.AddCommand(&naistrix.Command{
+				Name:  "test",
+				Title: "Test command",
+				Flags: flags,
+				RunFunc: func(context.Context, *naistrix.Arguments, *naistrix.OutputWriter) error {
+					return nil
+				},
+			})
+			if err != nil {
+				t.Fatalf("unable to add command: %v", err)
+			}
+
+			err = app.Run(naistrix.RunWithArgs(append([]string{"test", "--config", configPath}, tt.args...)))
+			if tt.expectedErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.expectedErr) {
+					t.Fatalf("expected error to contain %q, got: %v", tt.expectedErr, err)
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !flags.Since.Equal(expected.Since) {
+				t.Fatalf("expected since to be %v, got: %v", expected.Since, flags.Since)
+			}
+			flags.Since = expected.Since
+
+			if !reflect.DeepEqual(*flags, expected) {
+				t.Fatalf("expected flags to be %+v, got: %+v", expected, *flags)
+			}
+		})
+	}
+}
+
+func TestParseByteSize(t *testing.T) {
+	tests := []struct {
+		input       string
+		expected    naistrix.ByteSize
+		expectedStr string
+		expectedErr bool
+	}{
+		{input: "1024", expected: 1024, expectedStr: "1KiB"},
+		{input: "500MB", expected: 500_000_000, expectedStr: "500000000B"},
+		{input: "1.5G", expected: 1_500_000_000, expectedStr: "1500000000B"},
+		{input: "512ki", expected: 512 * 1024, expectedStr: "512KiB"},
+		{input: "2 GiB", expected: 2 << 30, expectedStr: "2GiB"},
+		{input: "0", expected: 0, expectedStr: "0B"},
+		{input: "-1", expectedErr: true},
+		{input: "10 parsecs", expectedErr: true},
+		{input: "", expectedErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			size, err := naistrix.ParseByteSize(tt.input)
+			if tt.expectedErr {
+				if err == nil {
+					t.Fatalf("expected error, got %v", size)
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if size != tt.expected {
+				t.Fatalf("expected %d, got %d", tt.expected, size)
+			}
+
+			if str := size.String(); str != tt.expectedStr {
+				t.Fatalf("expected %q, got %q", tt.expectedStr, str)
+			}
+		})
+	}
+}
+
+func TestTimeFlag_Relative(t *testing.T) {
+	app, _, err := naistrix.NewApplication("app", "title", "v0.0.0", naistrix.ApplicationWithWriter(io.Discard))
+	if err != nil {
+		t.Fatalf("unable to create application: %v", err)
+	}
+
+	flags := &struct {
+		Since time.Time `name:"since"`
+		Until time.Time `name:"until"`
+	}{}
+	err = app.AddCommand(&naistrix.Command{
+		Name:  "test",
+		Title: "Test command",
+		Flags: flags,
+		RunFunc: func(context.Context, *naistrix.Arguments, *naistrix.OutputWriter) error {
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("unable to add command: %v", err)
+	}
+
+	if err := app.Run(naistrix.RunWithArgs([]string{"test", "--since", "-2h", "--until", "+1d"})); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if d := time.Since(flags.Since); d < 2*time.Hour || d > 2*time.Hour+time.Minute {
+		t.Fatalf("expected since to be two hours ago, got: %v", flags.Since)
+	}
+
+	if d := time.Until(flags.Until); d > 24*time.Hour || d < 24*time.Hour-time.Minute {
+		t.Fatalf("expected until to be a day from now, got: %v", flags.Until)
+	}
+}
+
+type environment string
+
+func (e *environment) String() string {
+	return string(*e)
+}
+
+func (e *environment) Set(value string) error {
+	if value != "dev" && value != "prod" {
+		return fmt.Errorf("unknown environment %q", value)
+	}
+
+	*e = environment(value)
+	return nil
+}
+
+func (e *environment) Type() string {
+	return "environment"
+}
+
+type headers []string
+
+func (h *headers) String() string {
+	return strings.Join(*h, ",")
+}
+
+func (h *headers) Set(value string) error {
+	*h = append(*h, value)
+	return nil
+}
+
+func (h *headers) Type() string {
+	return "header"
+}
+
+func TestCustomFlagValues(t *testing.T) {
+	tests := []struct {
+		name                string
+		args                []string
+		env                 map[string]string
+		config              string
+		expectedEnvironment environment
+		expectedLevel       slog.Level
+		expectedHeaders     headers
+		expectedErr         string
+	}{
+		{
+			name:                "defaults",
+			expectedEnvironment: "dev",
+			expectedLevel:       slog.LevelInfo,
+		},
+		{
+			name:                "command line",
+			args:                []string{"--environment", "prod", "--level", "debug"},
+			expectedEnvironment: "prod",
+			expectedLevel:       slog.LevelDebug,
+		},
+		{
+			name:                "repeated values on the command line",
+			args:                []string{"--header", "a=1", "--header", "b=2"},
+			expectedEnvironment: "dev",
+			expectedLevel:       slog.LevelInfo,
+			expectedHeaders:     headers{"a=1", "b=2"},
+		},
+		{
+			name:                "environment",
+			env:                 map[string]string{"APP_ENVIRONMENT": "prod", "APP_LEVEL": "warn"},
+			expectedEnvironment: "prod",
+			expectedLevel:       slog.LevelWarn,
+		},
+		{
+			name:                "configuration file",
+			config:              "environment: prod\nlevel: error\n",
+			expectedEnvironment: "prod",
+			expectedLevel:       slog.LevelError,
+		},
+		{
+			name:        "invalid value on the command line",
+			args:        []string{"--environment", "staging"},
+			expectedErr: `unknown environment "staging"`,
+		},
+		{
+			name:        "invalid value in the configuration file",
+			config:      "environment: staging\n",
+			expectedErr: `Invalid value for the --environment flag: unknown environment "staging"`,
+		},
+		{
+			name:        "invalid text value in the environment",
+			env:         map[string]string{"APP_LEVEL": "loud"},
+			expectedErr: "Invalid value for the --level flag",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+
+			configPath := filepath.Join(t.TempDir(), "config.yaml")
+			if err := os.WriteFile(configPath, []byte(tt.config), 0o600); err != nil {
+				t.Fatalf("unable to write config file: %v", err)
+			}
+
+			app, _, err := naistrix.NewApplication("app", "title", "v0.0.0", naistrix.ApplicationWithWriter(io.Discard))
+			if err != nil {
+				t.Fatalf("unable to create application: %v", err)
+			}
+
+			flags := &struct {
+				Environment environment `name:"environment"`
+				Level       slog.Level  `name:"level"`
+				Headers     headers     `name:"header"`
+			}{Environment: "dev"}
+			err = app.AddCommand(&naistrix.Command{
+				Name:  "test",
+				Title: "Test command",
+				Flags: flags,
+				RunFunc: func(context.Context, *naistrix.Arguments, *naistrix.OutputWriter) error {
+					return nil
+				},
+			})
+			if err != nil {
+				t.Fatalf("unable to add command: %v", err)
+			}
+
+			err = app.Run(naistrix.RunWithArgs(append([]string{"test", "--config", configPath}, tt.args...)))
+			if tt.expectedErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.expectedErr) {
+					t.Fatalf("expected error to contain %q, got: %v", tt.expectedErr, err)
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if flags.Environment != tt.expectedEnvironment {
+				t.Fatalf("expected environment %q, got: %q", tt.expectedEnvironment, flags.Environment)
+			}
+
+			if flags.Level != tt.expectedLevel {
+				t.Fatalf("expected level %v, got: %v", tt.expectedLevel, flags.Level)
+			}
+
+			if !slices.Equal(flags.Headers, tt.expectedHeaders) {
+				t.Fatalf("expected headers %q, got: %q", tt.expectedHeaders, flags.Headers)
+			}
+		})
+	}
+}
+
+func TestCustomFlagValues_Help(t *testing.T) {
+	var buf bytes.Buffer
+	app, _, err := naistrix.NewApplication("app", "title", "v0.0.0", naistrix.ApplicationWithWriter(&buf))
+	if err != nil {
+		t.Fatalf("unable to create application: %v", err)
+	}
+
+	err = app.AddCommand(&naistrix.Command{
+		Name:  "test",
+		Title: "Test command",
+		Flags: &struct {
+			Environment environment `name:"environment"`
+			Level       slog.Level  `name:"level"`
+		}{Environment: "dev"},
+		RunFunc: func(context.Context, *naistrix.Arguments, *naistrix.OutputWriter) error {
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("unable to add command: %v", err)
+	}
+
+	if err := app.Run(naistrix.RunWithArgs([]string{"test", "--help", "--color=never"})); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, expected := range []string{"--environment environment", "(default dev)", "--level level", "(default INFO)"} {
+		if !strings.Contains(buf.String(), expected) {
+			t.Errorf("expected help to contain %q, got: %s", expected, buf.String())
+		}
+	}
+}
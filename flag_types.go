@@ -0,0 +1,245 @@
+package naistrix
+
+import (
+	"encoding"
+	"fmt"
+	"math"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/spf13/pflag"
+)
+
+// ByteSize is a type used for flags that hold a size in bytes. Sizes can be given as a number of bytes, or with a
+// decimal unit, like "500MB" and "1.5G", or a binary unit, like "512Ki" and "2GiB". Units are case-insensitive.
+type ByteSize uint64
+
+// byteSizeUnits are the units that can be used for byte sizes, and their sizes in bytes.
+var byteSizeUnits = map[string]float64{
+	"":    1,
+	"b":   1,
+	"k":   1e3,
+	"kb":  1e3,
+	"ki":  1 << 10,
+	"kib": 1 << 10,
+	"m":   1e6,
+	"mb":  1e6,
+	"mi":  1 << 20,
+	"mib": 1 << 20,
+	"g":   1e9,
+	"gb":  1e9,
+	"gi":  1 << 30,
+	"gib": 1 << 30,
+	"t":   1e12,
+	"tb":  1e12,
+	"ti":  1 << 40,
+	"tib": 1 << 40,
+	"p":   1e15,
+	"pb":  1e15,
+	"pi":  1 << 50,
+	"pib": 1 << 50,
+}
+
+// byteSizeNames are the binary units used when formatting byte sizes, from the largest to the smallest.
+var byteSizeNames = []struct {
+	name string
+	size ByteSize
+}{
+	{"PiB", 1 << 50},
+	{"TiB", 1 << 40},
+	{"GiB", 1 << 30},
+	{"MiB", 1 << 20},
+	{"KiB", 1 << 10},
+}
+
+// ParseByteSize parses a size in bytes, like "1024", "500MB" or "1.5GiB". See [ByteSize] for the supported units.
+func ParseByteSize(s string) (ByteSize, error) {
+	s = strings.TrimSpace(s)
+	i := strings.IndexFunc(s, func(r rune) bool {
+		return unicode.IsLetter(r)
+	})
+	if i < 0 {
+		i = len(s)
+	}
+
+	number, unit := strings.TrimSpace(s[:i]), strings.ToLower(s[i:])
+	multiplier, ok := byteSizeUnits[unit]
+	if !ok {
+		return 0, fmt.Errorf("invalid size %q: unknown unit %q", s, s[i:])
+	}
+
+	f, err := strconv.ParseFloat(number, 64)
+	if err != nil || f < 0 || math.IsInf(f, 0) {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+
+	size := f * multiplier
+	if size >= math.MaxUint64 {
+		return 0, fmt.Errorf("invalid size %q: too large", s)
+	}
+
+	return ByteSize(math.Round(size)), nil
+}
+
+// String returns the size using the largest binary unit that represents it exactly, like "512MiB", falling back to
+// the number of bytes, like "1500B".
+func (b ByteSize) String() string {
+	for _, unit := range byteSizeNames {
+		if b >= unit.size && b%unit.size == 0 {
+			return strconv.FormatUint(uint64(b/unit.size), 10) + unit.name
+		}
+	}
+
+	return strconv.FormatUint(uint64(b), 10) + "B"
+}
+
+// Set parses the size, see [ParseByteSize]. This method satisfies the [pflag.Value] interface.
+func (b *ByteSize) Set(s string) error {
+	size, err := ParseByteSize(s)
+	if err != nil {
+		return err
+	}
+
+	*b = size
+	return nil
+}
+
+// Type returns the name of the type shown in help output. This method satisfies the [pflag.Value] interface.
+func (b *ByteSize) Type() string {
+	return "size"
+}
+
+// timeValue is a [pflag.Value] for [time.Time] flags.
+type timeValue struct {
+	ptr *time.Time
+}
+
+// Set parses a timestamp in the RFC 3339 format, like "2006-01-02T15:04:05Z07:00", a date, like "2006-01-02", or a
+// time relative to now, like "-2h" for two hours ago or "+7d" for seven days from now.
+func (t timeValue) Set(s string) error {
+	ts, err := parseTime(s, time.Now())
+	if err != nil {
+		return err
+	}
+
+	*t.ptr = ts
+	return nil
+}
+
+func (t timeValue) String() string {
+	if t.ptr == nil || t.ptr.IsZero() {
+		return ""
+	}
+	return t.ptr.Format(time.RFC3339Nano)
+}
+
+func (t timeValue) Type() string {
+	return "time"
+}
+
+// parseTime parses s as described in [timeValue.Set], where relative times are relative to now.
+func parseTime(s string, now time.Time) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	for _, layout := range []string{time.RFC3339Nano, time.DateOnly} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		if n, err := strconv.ParseFloat(days, 64); err == nil {
+			return now.Add(time.Duration(n * float64(24*time.Hour))), nil
+		}
+	}
+
+	if d, err := time.ParseDuration(s); err == nil {
+		return now.Add(d), nil
+	}
+
+	return time.Time{}, fmt.Errorf("invalid time %q, must be in the RFC 3339 format, a date, or relative like -2h", s)
+}
+
+// urlValue is a [pflag.Value] for [url.URL] flags.
+type urlValue struct {
+	ptr **url.URL
+}
+
+// Set parses an absolute URL, like "https://example.com/path".
+func (u urlValue) Set(s string) error {
+	parsed, err := url.Parse(strings.TrimSpace(s))
+	if err != nil {
+		return fmt.Errorf("invalid URL %q: %w", s, err)
+	}
+
+	if !parsed.IsAbs() || parsed.Host == "" {
+		return fmt.Errorf("invalid URL %q, must be an absolute URL like https://example.com", s)
+	}
+
+	*u.ptr = parsed
+	return nil
+}
+
+func (u urlValue) String() string {
+	if u.ptr == nil || *u.ptr == nil {
+		return ""
+	}
+	return (*u.ptr).String()
+}
+
+func (u urlValue) Type() string {
+	return "url"
+}
+
+// textValue is a [pflag.Value] for types implementing [encoding.TextUnmarshaler].
+type textValue struct {
+	ptr encoding.TextUnmarshaler
+}
+
+func (t textValue) Set(s string) error {
+	return t.ptr.UnmarshalText([]byte(s))
+}
+
+func (t textValue) String() string {
+	switch v := t.ptr.(type) {
+	case encoding.TextMarshaler:
+		b, err := v.MarshalText()
+		if err != nil {
+			return ""
+		}
+		return string(b)
+	case fmt.Stringer:
+		return v.String()
+	default:
+		return fmt.Sprint(reflect.ValueOf(t.ptr).Elem().Interface())
+	}
+}
+
+// Type returns the lower case name of the type, like "level" for [slog.Level].
+func (t textValue) Type() string {
+	if name := reflect.TypeOf(t.ptr).Elem().Name(); name != "" {
+		return strings.ToLower(name)
+	}
+	return "value"
+}
+
+// flagValue returns the [pflag.Value] used for flags of types that are not supported by pflag, or false if ptr is not
+// a pointer to such a type. Types implementing [FlagValue] are used as is, and types implementing
+// [encoding.TextUnmarshaler] are parsed using the interface.
+func flagValue(ptr any) (pflag.Value, bool) {
+	switch p := ptr.(type) {
+	case *time.Time:
+		return timeValue{ptr: p}, true
+	case **url.URL:
+		return urlValue{ptr: p}, true
+	case FlagValue:
+		return p, true
+	case encoding.TextUnmarshaler:
+		return textValue{ptr: p}, true
+	default:
+		return nil, false
+	}
+}
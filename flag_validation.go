@@ -0,0 +1,239 @@
+package naistrix
+
+import (
+	"fmt"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/spf13/viper"
+)
+
+// flagValidator validates the value of a flag, and returns an error describing why the value is not valid.
+type flagValidator func(v reflect.Value) error
+
+// dns1123Label matches DNS-1123 labels, like the names of most Kubernetes resources.
+var dns1123Label = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
+// flagFormats are the formats that can be used with the `validate` struct tag, and the functions used to check them.
+var flagFormats = map[string]func(s string) error{
+	"email": func(s string) error {
+		if addr, err := mail.ParseAddress(s); err != nil || addr.Name != "" || addr.Address != s {
+			return fmt.Errorf("%q is not a valid email address", s)
+		}
+		return nil
+	},
+	"url": func(s string) error {
+		if u, err := url.Parse(s); err != nil || !u.IsAbs() || u.Host == "" {
+			return fmt.Errorf("%q is not a valid URL, must be an absolute URL like https://example.com", s)
+		}
+		return nil
+	},
+	"dns1123": func(s string) error {
+		if len(s) > 63 || !dns1123Label.MatchString(s) {
+			return fmt.Errorf(
+				"%q is not a valid DNS-1123 label, must be at most 63 lower case alphanumeric characters or '-', and "+
+					"start and end with an alphanumeric character",
+				s,
+			)
+		}
+		return nil
+	},
+}
+
+// getFlagValidators returns the validators set using the `min`, `max`, `minlen`, `pattern` and `validate` struct tags
+// on the field of a flag, or an error if a tag is not valid for the type of the field.
+func getFlagValidators(field reflect.StructField) ([]flagValidator, error) {
+	validators := make([]flagValidator, 0)
+
+	for _, key := range []string{"min", "max"} {
+		tag, ok := field.Tag.Lookup(key)
+		if !ok {
+			continue
+		}
+
+		bound, err := parseFlagBound(field.Type, tag)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for the %s tag: %w", key, err)
+		}
+
+		validators = append(validators, func(v reflect.Value) error {
+			n, _ := numericValue(v)
+			switch {
+			case key == "min" && n < bound:
+				return fmt.Errorf("must be at least %s, got %v", tag, v.Interface())
+			case key == "max" && n > bound:
+				return fmt.Errorf("must be at most %s, got %v", tag, v.Interface())
+			}
+			return nil
+		})
+	}
+
+	if tag, ok := field.Tag.Lookup("minlen"); ok {
+		minLen, err := strconv.Atoi(tag)
+		if err != nil || minLen < 0 {
+			return nil, fmt.Errorf("invalid value for the minlen tag: %q", tag)
+		}
+
+		switch field.Type.Kind() {
+		case reflect.String:
+			validators = append(validators, func(v reflect.Value) error {
+				if utf8.RuneCountInString(v.String()) < minLen {
+					return fmt.Errorf("must be at least %d character%s long", minLen, plural(minLen))
+				}
+				return nil
+			})
+		case reflect.Slice, reflect.Map:
+			validators = append(validators, func(v reflect.Value) error {
+				if v.Len() < minLen {
+					return fmt.Errorf("must have at least %d value%s", minLen, plural(minLen))
+				}
+				return nil
+			})
+		default:
+			return nil, fmt.Errorf("the minlen tag is only supported for string, slice and map flags")
+		}
+	}
+
+	if tag, ok := field.Tag.Lookup("pattern"); ok {
+		if !isStringFlag(field.Type) {
+			return nil, fmt.Errorf("the pattern tag is only supported for string and []string flags")
+		}
+
+		re, err := regexp.Compile("^(?:" + tag + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for the pattern tag: %w", err)
+		}
+
+		validators = append(validators, eachString(func(s string) error {
+			if !re.MatchString(s) {
+				return fmt.Errorf("%q must match the pattern %s", s, tag)
+			}
+			return nil
+		}))
+	}
+
+	if tag, ok := field.Tag.Lookup("validate"); ok {
+		if !isStringFlag(field.Type) {
+			return nil, fmt.Errorf("the validate tag is only supported for string and []string flags")
+		}
+
+		check, ok := flagFormats[tag]
+		if !ok {
+			return nil, fmt.Errorf("unknown format %q, must be one of: email, url, dns1123", tag)
+		}
+
+		validators = append(validators, eachString(check))
+	}
+
+	return validators, nil
+}
+
+// parseFlagBound parses the value of a `min` or `max` struct tag for a field of type t. Bounds for [time.Duration] and
+// [ByteSize] flags are parsed the same way as the values of the flags, like "30s" and "10MiB".
+func parseFlagBound(t reflect.Type, s string) (float64, error) {
+	switch t {
+	case reflect.TypeFor[time.Duration]():
+		d, err := time.ParseDuration(s)
+		return float64(d), err
+	case reflect.TypeFor[ByteSize]():
+		b, err := ParseByteSize(s)
+		return float64(b), err
+	}
+
+	if _, ok := numericValue(reflect.Zero(t)); !ok {
+		return 0, fmt.Errorf("only supported for numeric flags")
+	}
+
+	return strconv.ParseFloat(s, 64)
+}
+
+// numericValue returns the value of a numeric flag as a float64, or false if the flag is not numeric.
+func numericValue(v reflect.Value) (float64, bool) {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	default:
+		return 0, false
+	}
+}
+
+// isStringFlag checks if t is a string or a slice of strings.
+func isStringFlag(t reflect.Type) bool {
+	return t.Kind() == reflect.String || (t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.String)
+}
+
+// eachString returns a validator that runs check for the value of string flags, and for each value of slice flags.
+func eachString(check func(s string) error) flagValidator {
+	return func(v reflect.Value) error {
+		if v.Kind() == reflect.String {
+			return check(v.String())
+		}
+
+		for i := range v.Len() {
+			if err := check(v.Index(i).String()); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// validateFlagValues validates the values of the flags in the flags structs using the validators set with struct tags,
+// see [getFlagValidators]. Only flags set on the command line, in the configuration file or using environment variables
+// are validated, so the values must be synced to the flags structs first. All invalid flags are reported to the
+// end-user in a single [Error].
+func validateFlagValues(config *viper.Viper, flags ...any) error {
+	var names []string
+	var errs []error
+	for _, f := range flags {
+		if f == nil {
+			continue
+		}
+
+		fields := reflect.TypeOf(f).Elem()
+		values := reflect.ValueOf(f).Elem()
+		for i := range fields.NumField() {
+			field := fields.Field(i)
+			if field.Anonymous || !field.IsExported() {
+				continue
+			}
+
+			flagName := getFlagName(field)
+			if !config.IsSet(flagName) {
+				continue
+			}
+
+			// the tags are validated when the flags are set up
+			validators, _ := getFlagValidators(field)
+			for _, validate := range validators {
+				if err := validate(values.Field(i)); err != nil {
+					names, errs = append(names, flagName), append(errs, err)
+					break
+				}
+			}
+		}
+	}
+
+	switch len(errs) {
+	case 0:
+		return nil
+	case 1:
+		return Errorf("Invalid value for the --%s flag: %v", names[0], errs[0])
+	}
+
+	lines := make([]string, len(errs))
+	for i, err := range errs {
+		lines[i] = fmt.Sprintf("  --%s: %v", names[i], err)
+	}
+	return Errorf("Invalid values for %d flags:\n%s", len(errs), strings.Join(lines, "\n"))
+}
@@ -0,0 +1,203 @@
+package naistrix_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nais/naistrix"
+)
+
+type validatedFlags struct {
+	Replicas int               `name:"replicas" min:"1" max:"10"`
+	Timeout  time.Duration     `name:"timeout" min:"1s"`
+	Memory   naistrix.ByteSize `name:"memory" max:"1GiB"`
+	Name     string            `name:"name" validate:"dns1123"`
+	Email    string            `name:"email" validate:"email"`
+	Homepage string            `name:"homepage" validate:"url"`
+	Version  string            `name:"version" pattern:"v[0-9]+"`
+	Secret   string            `name:"secret" minlen:"8"`
+	Teams    []string          `name:"team" minlen:"1" pattern:"[a-z]+"`
+}
+
+func TestFlagValidation(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        []string
+		env         map[string]string
+		config      string
+		expectedErr string
+	}{
+		{
+			name: "valid values",
+			args: []string{
+				"--replicas", "10",
+				"--timeout", "1s",
+				"--memory", "512MiB",
+				"--name", "my-app",
+				"--email", "nais@example.com",
+				"--homepage", "https://example.com",
+				"--version", "v12",
+				"--secret", "hunter22",
+				"--team", "nais,aura",
+			},
+		},
+		{
+			name: "default values are not validated",
+		},
+		{
+			name:        "single invalid value",
+			args:        []string{"--replicas", "0"},
+			expectedErr: "Invalid value for the --replicas flag: must be at least 1, got 0",
+		},
+		{
+			name: "all invalid values are reported",
+			args: []string{
+				"--replicas", "11",
+				"--timeout", "500ms",
+				"--memory", "2GiB",
+				"--name", "My_App",
+				"--email", "Nais <nais@example.com>",
+				"--homepage", "example.com",
+				"--version", "12",
+				"--secret", "hunter2",
+				"--team", "nais,Aura",
+			},
+			expectedErr: "Invalid values for 9 flags:\n" +
+				"  --replicas: must be at most 10, got 11\n" +
+				"  --timeout: must be at least 1s, got 500ms\n" +
+				"  --memory: must be at most 1GiB, got 2GiB\n" +
+				`  --name: "My_App" is not a valid DNS-1123 label, must be at most 63 lower case alphanumeric ` +
+				"characters or '-', and start and end with an alphanumeric character\n" +
+				`  --email: "Nais <nais@example.com>" is not a valid email address` + "\n" +
+				`  --homepage: "example.com" is not a valid URL, must be an absolute URL like https://example.com` + "\n" +
+				`  --version: "12" must match the pattern v[0-9]+` + "\n" +
+				"  --secret: must be at least 8 characters long\n" +
+				`  --team: "Aura" must match the pattern [a-z]+`,
+		},
+		{
+			name:   "values from the configuration file and the environment",
+			env:    map[string]string{"APP_REPLICAS": "20"},
+			config: "team: []\nversion: latest\n",
+			expectedErr: "Invalid values for 3 flags:\n" +
+				"  --replicas: must be at most 10, got 20\n" +
+				`  --version: "latest" must match the pattern v[0-9]+` + "\n" +
+				"  --team: must have at least 1 value",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+
+			configPath := filepath.Join(t.TempDir(), "config.yaml")
+			if err := os.WriteFile(configPath, []byte(tt.config), 0o600); err != nil {
+				t.Fatalf("unable to write config file: %v", err)
+			}
+
+			app, _, err := naistrix.NewApplication("app", "title", "v0.0.0")
+			if err != nil {
+				t.Fatalf("unable to create application: %v", err)
+			}
+
+			err = app.AddCommand(&naistrix.Command{
+				Name:  "test",
+				Title: "Test command",
+				Flags: &validatedFlags{},
+				RunFunc: func(context.Context, *naistrix.Arguments, *naistrix.OutputWriter) error {
+					return nil
+				},
+			})
+			if err != nil {
+				t.Fatalf("unable to add command: %v", err)
+			}
+
+			err = app.Run(naistrix.RunWithArgs(append([]string{"test", "--config", configPath}, tt.args...)))
+			if tt.expectedErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			e, ok := err.(naistrix.Error)
+			if !ok {
+				t.Fatalf("expected a naistrix.Error, got %T: %v", err, err)
+			}
+
+			if e.Message != tt.expectedErr {
+				t.Fatalf("expected error:\n%s\ngot:\n%s", tt.expectedErr, e.Message)
+			}
+		})
+	}
+}
+
+func TestFlagValidation_Invalid(t *testing.T) {
+	tests := []struct {
+		name        string
+		flags       any
+		expectedErr string
+	}{
+		{
+			name: "min on a string flag",
+			flags: &struct {
+				Name string `min:"1"`
+			}{},
+			expectedErr: "invalid value for the min tag: only supported for numeric flags",
+		},
+		{
+			name: "invalid max value",
+			flags: &struct {
+				Timeout time.Duration `max:"ten minutes"`
+			}{},
+			expectedErr: "invalid value for the max tag",
+		},
+		{
+			name: "minlen on an int flag",
+			flags: &struct {
+				Count int `minlen:"1"`
+			}{},
+			expectedErr: "the minlen tag is only supported for string, slice and map flags",
+		},
+		{
+			name: "invalid pattern",
+			flags: &struct {
+				Name string `pattern:"[a-z"`
+			}{},
+			expectedErr: "invalid value for the pattern tag",
+		},
+		{
+			name: "unknown format",
+			flags: &struct {
+				Name string `validate:"ipv4"`
+			}{},
+			expectedErr: `unknown format "ipv4", must be one of: email, url, dns1123`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app, _, err := naistrix.NewApplication("app", "title", "v0.0.0")
+			if err != nil {
+				t.Fatalf("unable to create application: %v", err)
+			}
+
+			err = app.AddCommand(&naistrix.Command{
+				Name:  "test",
+				Title: "Test command",
+				Flags: tt.flags,
+				RunFunc: func(context.Context, *naistrix.Arguments, *naistrix.OutputWriter) error {
+					return nil
+				},
+			})
+			if err == nil || !strings.Contains(err.Error(), tt.expectedErr) {
+				t.Fatalf("expected error to contain %q, got: %v", tt.expectedErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,185 @@
+package naistrix
+
+import (
+	"errors"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/nais/naistrix/output"
+	"github.com/spf13/cobra"
+)
+
+// OutputFormat is the name of an output format that can be selected by the end-user using the global --output flag.
+type OutputFormat string
+
+// Built-in output formats that are always registered.
+const (
+	// OutputFormatTable renders data using [output.Table].
+	OutputFormatTable OutputFormat = "table"
+	// OutputFormatWide renders data using [output.Table], including the columns with the `wide:"true"` tag.
+	OutputFormatWide OutputFormat = "wide"
+	// OutputFormatJSON renders data using [output.JSON]. An optional query expression can be passed as an argument to
+	// the format, like for instance "json=.items[0]".
+	OutputFormatJSON OutputFormat = "json"
+	// OutputFormatYAML renders data using [output.YAML]. An optional query expression can be passed as an argument to
+	// the format, like for instance "yaml=.items[0]".
+	OutputFormatYAML OutputFormat = "yaml"
+	// OutputFormatNDJSON renders data using [output.NDJSON].
+	OutputFormatNDJSON OutputFormat = "ndjson"
+	// OutputFormatJSONPath renders the values selected by a template using [output.JSONPath]. The template is passed as
+	// an argument to the format, like for instance "jsonpath={.name}".
+	OutputFormatJSONPath OutputFormat = "jsonpath"
+	// OutputFormatGoTemplate renders data using a Go template with [output.Template]. The template is passed as an
+	// argument to the format, like for instance "go-template={{.Name}}".
+	OutputFormatGoTemplate OutputFormat = "go-template"
+	// OutputFormatCSV renders data using [output.CSV].
+	OutputFormatCSV OutputFormat = "csv"
+	// OutputFormatTSV renders data using [output.TSV].
+	OutputFormatTSV OutputFormat = "tsv"
+)
+
+// defaultOutputFormat is the output format used by [OutputWriter.Render] when the end-user has not selected a format,
+// and the command does not declare any supported formats.
+const defaultOutputFormat = OutputFormatTable
+
+// outputFormatsAnnotation is the cobra.Command annotation used to store the output formats supported by a command.
+const outputFormatsAnnotation = "naistrix/output-formats"
+
+// OutputFormatFunc is a function that creates the renderer for an output format. The returned renderer must write to w.
+//
+// The arg parameter holds the optional argument passed along with the format name, for instance "expr" when the
+// end-user runs a command with "--output format=expr". An error should be returned if the argument is invalid for the
+// format. The function is also called before the command is run to validate the argument, and renderers implementing
+// [output.Validator] are validated at that point as well.
+type OutputFormatFunc func(w io.Writer, arg string) (output.Renderer, error)
+
+// outputFormats is a registry of output formats, keyed by the name of the format.
+type outputFormats map[OutputFormat]OutputFormatFunc
+
+// defaultOutputFormats returns a registry with the built-in output formats.
+func defaultOutputFormats() outputFormats {
+	return outputFormats{
+		OutputFormatTable: func(w io.Writer, _ string) (output.Renderer, error) {
+			return output.NewTable(w), nil
+		},
+		OutputFormatWide: func(w io.Writer, _ string) (output.Renderer, error) {
+			return output.NewTable(w, output.TableWithWideColumns()), nil
+		},
+		OutputFormatJSON: func(w io.Writer, query string) (output.Renderer, error) {
+			return output.NewJSON(w, output.JSONWithPrettyOutput(), output.JSONWithQuery(query)), nil
+		},
+		OutputFormatYAML: func(w io.Writer, query string) (output.Renderer, error) {
+			return output.NewYAML(w, output.YAMLWithQuery(query)), nil
+		},
+		OutputFormatJSONPath: func(w io.Writer, template string) (output.Renderer, error) {
+			if template == "" {
+				return nil, Errorf("The %s output format requires a template, for instance: %[1]s={.name}", OutputFormatJSONPath)
+			}
+			return output.NewJSONPath(w, template), nil
+		},
+		OutputFormatGoTemplate: func(w io.Writer, text string) (output.Renderer, error) {
+			if text == "" {
+				return nil, Errorf("The %s output format requires a template, for instance: %[1]s={{.Name}}", OutputFormatGoTemplate)
+			}
+			return output.NewTemplate(w, text), nil
+		},
+		OutputFormatNDJSON: func(w io.Writer, _ string) (output.Renderer, error) {
+			return output.NewNDJSON(w), nil
+		},
+		OutputFormatCSV: func(w io.Writer, _ string) (output.Renderer, error) {
+			return output.NewCSV(w), nil
+		},
+		OutputFormatTSV: func(w io.Writer, _ string) (output.Renderer, error) {
+			return output.NewTSV(w), nil
+		},
+	}
+}
+
+// names returns the sorted names of all registered output formats.
+func (f outputFormats) names() []OutputFormat {
+	return slices.Sorted(maps.Keys(f))
+}
+
+// parseOutputFormat splits the value of the --output flag into the format name and the optional argument.
+func parseOutputFormat(value string) (OutputFormat, string) {
+	name, arg, _ := strings.Cut(strings.TrimSpace(value), "=")
+	return OutputFormat(name), arg
+}
+
+// joinOutputFormats concatenates the names of the provided output formats, separated by sep.
+func joinOutputFormats(formats []OutputFormat, sep string) string {
+	s := make([]string, len(formats))
+	for i, f := range formats {
+		s[i] = string(f)
+	}
+	return strings.Join(s, sep)
+}
+
+// commandOutputFormats returns the output formats supported by the provided cobra.Command. If the command does not
+// declare any output formats, all registered formats are returned.
+func commandOutputFormats(cmd *cobra.Command, registered outputFormats) []OutputFormat {
+	if cmd != nil {
+		if a, ok := cmd.Annotations[outputFormatsAnnotation]; ok && a != "" {
+			ret := make([]OutputFormat, 0)
+			for f := range strings.SplitSeq(a, ",") {
+				ret = append(ret, OutputFormat(f))
+			}
+			return ret
+		}
+	}
+
+	return registered.names()
+}
+
+// outputFlagUsage returns the usage text of the global --output flag, listing the provided formats.
+func outputFlagUsage(formats []OutputFormat) string {
+	return "Set the output `FORMAT`. One of: " + joinOutputFormats(formats, ", ") + "."
+}
+
+// validateOutputFormat checks that the output format selected by the end-user is registered and supported by the
+// command, and that the argument passed along with the format is valid. Renderers implementing [output.Validator] are
+// validated as well, so invalid queries and templates are reported before the command is run.
+func validateOutputFormat(value string, cmd *cobra.Command, registered outputFormats) error {
+	if strings.TrimSpace(value) == "" {
+		return nil
+	}
+
+	name, arg := parseOutputFormat(value)
+	supported := commandOutputFormats(cmd, registered)
+	if !slices.Contains(supported, name) {
+		return Errorf("Unsupported output format %q, must be one of: %s", name, joinOutputFormats(supported, ", "))
+	}
+
+	fn, ok := registered[name]
+	if !ok {
+		return nil
+	}
+
+	r, err := fn(io.Discard, arg)
+	if err != nil {
+		return renderError(err)
+	}
+
+	if v, ok := r.(output.Validator); ok {
+		if err := v.Validate(); err != nil {
+			return renderError(err)
+		}
+	}
+
+	return nil
+}
+
+// renderError converts errors caused by invalid query expressions and templates to errors reported to the end-user.
+// It is only used for errors returned by the renderers of the framework, so that errors returned by the commands are
+// left as is, including errors wrapping query and template errors.
+func renderError(err error) error {
+	if e, ok := errors.AsType[*output.QueryError](err); ok {
+		return Errorf("Invalid query %q: %s at position %d", e.Expression, e.Reason, e.Position)
+	}
+	if e, ok := errors.AsType[*output.TemplateError](err); ok {
+		return Errorf("Invalid template: %v", e.Err)
+	}
+	return err
+}
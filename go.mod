@@ -15,6 +15,8 @@ tool (
 require (
 	atomicgo.dev/keyboard v0.2.10
 	github.com/MakeNowJust/heredoc/v2 v2.0.1
+	github.com/gookit/color v1.6.1
+	github.com/mattn/go-runewidth v0.0.23
 	github.com/pterm/pterm v0.12.83
 	github.com/savioxavier/termlink v1.4.3
 	github.com/spf13/cobra v1.10.2
@@ -58,14 +60,12 @@ require (
 	github.com/google/uuid v1.6.0 // indirect
 	github.com/googleapis/enterprise-certificate-proxy v0.3.14 // indirect
 	github.com/googleapis/gax-go/v2 v2.22.0 // indirect
-	github.com/gookit/color v1.6.1 // indirect
 	github.com/gorilla/websocket v1.5.3 // indirect
 	github.com/inconshreveable/mousetrap v1.1.0 // indirect
 	github.com/invopop/jsonschema v0.13.0 // indirect
 	github.com/klauspost/compress v1.18.0 // indirect
 	github.com/lithammer/fuzzysearch v1.1.8 // indirect
 	github.com/mailru/easyjson v0.9.2 // indirect
-	github.com/mattn/go-runewidth v0.0.23 // indirect
 	github.com/mitchellh/go-homedir v1.1.0 // indirect
 	github.com/openai/openai-go/v3 v3.37.0 // indirect
 	github.com/opencontainers/go-digest v1.0.0 // indirect
@@ -2,45 +2,205 @@ package color
 
 import (
 	"fmt"
+	"maps"
 	"regexp"
+	"slices"
+	"strings"
+	"sync"
 
+	gookit "github.com/gookit/color"
 	"github.com/pterm/pterm"
 )
 
-// coloredText is a regular expression that matches custom tags for info, warn, and error formatting inline in a string.
-var coloredText = regexp.MustCompile(`<(info|warn|error)>(.*?)</(info|warn|error)>`)
+// tag is a regular expression that matches the opening and closing custom tags used for formatting inline in a string,
+// like <info> and </info>.
+var tag = regexp.MustCompile(`<(/?)([a-z][a-z0-9-]*)>`)
 
-// Colorize applies colorization to a string based on custom tags.
+// tagName is a regular expression that matches valid names of custom tags.
+var tagName = regexp.MustCompile(`^[a-z][a-z0-9-]*$`)
+
+// Theme maps the names of the custom tags to the colors and text attributes applied to the content of the tags.
+type Theme map[string][]pterm.Color
+
+// DefaultTheme returns the theme used unless another theme is set with [SetTheme].
+func DefaultTheme() Theme {
+	return Theme{
+		"info":    {pterm.FgLightCyan},
+		"warn":    {pterm.FgYellow},
+		"error":   {pterm.FgLightRed},
+		"success": {pterm.FgGreen},
+		"muted":   {pterm.FgGray},
+		"bold":    {pterm.Bold},
+		"code":    {pterm.FgCyan},
+	}
+}
+
+var (
+	themeMu sync.RWMutex
+	theme   = DefaultTheme()
+)
+
+// SetTheme sets the theme used by [Colorize] and [Strip].
+func SetTheme(t Theme) {
+	themeMu.Lock()
+	defer themeMu.Unlock()
+	theme = t
+}
+
+// currentTheme returns the theme set with [SetTheme].
+func currentTheme() Theme {
+	themeMu.RLock()
+	defer themeMu.RUnlock()
+	return theme
+}
+
+// colors are the names of the colors and text attributes that can be used in a theme.
+var colors = map[string]pterm.Color{
+	"default":       pterm.FgDefault,
+	"black":         pterm.FgBlack,
+	"red":           pterm.FgRed,
+	"green":         pterm.FgGreen,
+	"yellow":        pterm.FgYellow,
+	"blue":          pterm.FgBlue,
+	"magenta":       pterm.FgMagenta,
+	"cyan":          pterm.FgCyan,
+	"white":         pterm.FgWhite,
+	"gray":          pterm.FgGray,
+	"light-red":     pterm.FgLightRed,
+	"light-green":   pterm.FgLightGreen,
+	"light-yellow":  pterm.FgLightYellow,
+	"light-blue":    pterm.FgLightBlue,
+	"light-magenta": pterm.FgLightMagenta,
+	"light-cyan":    pterm.FgLightCyan,
+	"light-white":   pterm.FgLightWhite,
+	"bold":          pterm.Bold,
+	"faint":         pterm.Fuzzy,
+	"italic":        pterm.Italic,
+	"underline":     pterm.Underscore,
+}
+
+// ParseStyle parses a comma-separated list of color and text attribute names, like "bold,light-red".
+func ParseStyle(s string) ([]pterm.Color, error) {
+	ret := make([]pterm.Color, 0)
+	for name := range strings.SplitSeq(s, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+
+		c, ok := colors[name]
+		if !ok {
+			names := strings.Join(slices.Sorted(maps.Keys(colors)), ", ")
+			return nil, fmt.Errorf("unknown color %q, must be one of: %s", name, names)
+		}
+		ret = append(ret, c)
+	}
+
+	return ret, nil
+}
+
+// ValidTagName reports whether name can be used as the name of a custom tag.
+func ValidTagName(name string) bool {
+	return tagName.MatchString(name)
+}
+
+// detectedLevel is the color support detected for the terminal when the program started.
+var detectedLevel = gookit.TermColorLevel()
+
+// SetForceColors makes colors render even if the terminal does not appear to support colors, like when the output is
+// not written to a terminal. Calling it with force set to false restores the detected color support.
+func SetForceColors(force bool) {
+	level := detectedLevel
+	if force && level == gookit.LevelNo {
+		level = gookit.LevelRgb
+	}
+	gookit.ForceSetColorLevel(level)
+}
+
+// Colorize applies colorization to a string based on custom tags. Tags can be nested, and tags that are not part of
+// the theme, or that are not closed, are left as is.
 func Colorize(s string) string {
-	return coloredText.ReplaceAllStringFunc(s, func(s string) string {
-		m := coloredText.FindStringSubmatch(s)
-		openTag, content, closeTag := m[1], m[2], m[3]
+	return render(s, true)
+}
+
+// Strip removes the custom tags from a string, leaving only the content of the tags.
+func Strip(s string) string {
+	return render(s, false)
+}
+
+// Sprint colors s using the colors and text attributes of the named tag in the theme. Custom tags in s are left as is,
+// and s is returned as is if the tag is not part of the theme.
+func Sprint(name, s string) string {
+	for _, c := range currentTheme()[name] {
+		s = c.Sprint(s)
+	}
+	return s
+}
 
-		if openTag != closeTag {
-			return s
+// frame is an open tag in the string being rendered.
+type frame struct {
+	name    string
+	openTag string
+	content strings.Builder
+}
+
+// render replaces the custom tags in s with the content of the tags, colorized according to the theme if colorize is
+// set.
+func render(s string, colorize bool) string {
+	t := currentTheme()
+	stack := []*frame{{}}
+	last := 0
+
+	for _, loc := range tag.FindAllStringSubmatchIndex(s, -1) {
+		closing, name := loc[3] > loc[2], s[loc[4]:loc[5]]
+		if _, ok := t[name]; !ok {
+			continue
 		}
 
-		var printer func(...any) string
-		switch openTag {
-		case "info":
-			printer = pterm.FgLightCyan.Sprint
-		case "warn":
-			printer = pterm.FgYellow.Sprint
-		case "error":
-			printer = pterm.FgLightRed.Sprint
+		top := stack[len(stack)-1]
+		top.content.WriteString(s[last:loc[0]])
+		last = loc[1]
+
+		switch {
+		case !closing:
+			stack = append(stack, &frame{name: name, openTag: s[loc[0]:loc[1]]})
+		case top.name == name:
+			stack = stack[:len(stack)-1]
+			content := top.content.String()
+			if colorize {
+				for _, c := range t[name] {
+					content = c.Sprint(content)
+				}
+			}
+			stack[len(stack)-1].content.WriteString(content)
 		default:
-			return s
+			// closing tags that do not match the innermost open tag are left as is
+			top.content.WriteString(s[loc[0]:loc[1]])
 		}
+	}
 
-		return printer(content)
-	})
+	stack[len(stack)-1].content.WriteString(s[last:])
+
+	// tags that are not closed are left as is
+	for len(stack) > 1 {
+		top := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		stack[len(stack)-1].content.WriteString(top.openTag + top.content.String())
+	}
+
+	return stack[0].content.String()
 }
 
-// ColorizeAny applies colorization to a slice of values. Each value will be converted to a string.
+// ColorizeAny applies colorization to the strings in a slice of values. Values of other types, like errors, often hold
+// data from elsewhere, and are left as is.
 func ColorizeAny(s []any) []any {
 	ret := make([]any, len(s))
-	for i, str := range s {
-		ret[i] = Colorize(fmt.Sprint(str))
+	for i, v := range s {
+		if str, ok := v.(string); ok {
+			ret[i] = Colorize(str)
+		} else {
+			ret[i] = v
+		}
 	}
 	return ret
 }
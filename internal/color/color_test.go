@@ -1,6 +1,10 @@
 package color
 
-import "testing"
+import (
+	"testing"
+
+	gookit "github.com/gookit/color"
+)
 
 func TestColorize(t *testing.T) {
 	tests := []struct {
@@ -33,6 +37,21 @@ func TestColorize(t *testing.T) {
 			in:   "<info>Info</info>, <warn>Warn</warn>, and <error>Error</error> messages.",
 			out:  "\x1b[96mInfo\x1b[0m, \x1b[33mWarn\x1b[0m, and \x1b[91mError\x1b[0m messages.",
 		},
+		{
+			name: "new tags",
+			in:   "<success>ok</success> <muted>m</muted> <bold>b</bold> <code>c</code>",
+			out:  "\x1b[32mok\x1b[0m \x1b[90mm\x1b[0m \x1b[1mb\x1b[0m \x1b[36mc\x1b[0m",
+		},
+		{
+			name: "nested tags",
+			in:   "<bold>Run <code>app deploy</code> now</bold>",
+			out:  "\x1b[1mRun \x1b[36mapp deploy\x1b[0m\x1b[1m now\x1b[0m",
+		},
+		{
+			name: "unknown and unclosed tags",
+			in:   "set <key> <value> <info>unclosed",
+			out:  "set <key> <value> <info>unclosed",
+		},
 	}
 
 	for _, tt := range tests {
@@ -43,3 +62,86 @@ func TestColorize(t *testing.T) {
 		})
 	}
 }
+
+func TestStrip(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		out  string
+	}{
+		{
+			name: "no tags",
+			in:   "This is a test string.",
+			out:  "This is a test string.",
+		},
+		{
+			name: "mixed tags",
+			in:   "<info>Info</info>, <warn>Warn</warn>, and <error>Error</error> messages.",
+			out:  "Info, Warn, and Error messages.",
+		},
+		{
+			name: "mismatched tags",
+			in:   "<info>Info</warn> message.",
+			out:  "<info>Info</warn> message.",
+		},
+		{
+			name: "nested tags",
+			in:   "<bold>Run <code>app deploy</code> now</bold>",
+			out:  "Run app deploy now",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Strip(tt.in); got != tt.out {
+				t.Errorf("Strip() = %v, want %v", got, tt.out)
+			}
+		})
+	}
+}
+
+func TestSprint(t *testing.T) {
+	if got, want := Sprint("error", "<info>data</info>"), "\x1b[91m<info>data</info>\x1b[0m"; got != want {
+		t.Errorf("Sprint() = %q, want %q", got, want)
+	}
+
+	if got, want := Sprint("unknown", "data"), "data"; got != want {
+		t.Errorf("Sprint() = %q, want %q", got, want)
+	}
+}
+
+func TestSetTheme(t *testing.T) {
+	defer SetTheme(DefaultTheme())
+
+	style, err := ParseStyle("bold, light-red")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	theme := DefaultTheme()
+	theme["info"] = style
+	SetTheme(theme)
+
+	if got, want := Colorize("<info>Info</info>"), "\x1b[91m\x1b[1mInfo\x1b[0m\x1b[91m\x1b[0m"; got != want {
+		t.Errorf("Colorize() = %q, want %q", got, want)
+	}
+
+	if _, err := ParseStyle("bold,purple"); err == nil {
+		t.Error("expected error for unknown color")
+	}
+}
+
+func TestSetForceColors(t *testing.T) {
+	detected := gookit.TermColorLevel()
+	defer gookit.ForceSetColorLevel(detected)
+
+	SetForceColors(true)
+	if !gookit.SupportColor() {
+		t.Fatalf("expected colors to be supported when forced")
+	}
+
+	SetForceColors(false)
+	if level := gookit.TermColorLevel(); level != detected {
+		t.Fatalf("expected the detected color level %v to be restored, got %v", detected, level)
+	}
+}
@@ -0,0 +1,172 @@
+package output
+
+import (
+	"encoding/csv"
+	"io"
+)
+
+// CSVOptionFunc is a function that can be used to configure the [CSV] renderer.
+type CSVOptionFunc func(*CSV)
+
+// CSVWithShowHiddenColumns can be used to force rendering all exported fields in a struct, even if the field have the
+// `hidden:"true"` tag.
+func CSVWithShowHiddenColumns() CSVOptionFunc {
+	return func(c *CSV) {
+		c.selection.showHidden = true
+	}
+}
+
+// CSVWithColumns can be used to only write the columns with the provided headings, in the provided order. See
+// [TableWithColumns] for details.
+func CSVWithColumns(headings ...string) CSVOptionFunc {
+	return func(c *CSV) {
+		c.selection.columns = headings
+	}
+}
+
+// CSVWithSortBy can be used to sort the rows by one or more columns, identified by their headings. See
+// [TableWithSortBy] for details.
+func CSVWithSortBy(headings ...string) CSVOptionFunc {
+	return func(c *CSV) {
+		c.selection.sortBy = headings
+	}
+}
+
+// CSVWithFilters can be used to only write rows matching all the provided filters. See [TableWithFilters] for details.
+func CSVWithFilters(filters ...string) CSVOptionFunc {
+	return func(c *CSV) {
+		c.selection.filters = filters
+	}
+}
+
+// CSVWithoutHeader can be used to skip the header row.
+func CSVWithoutHeader() CSVOptionFunc {
+	return func(c *CSV) {
+		c.skipHeader = true
+	}
+}
+
+// CSV is a renderer that writes comma-separated values to an [io.Writer]. Use [NewCSV] to construct one.
+type CSV struct {
+	selection  rowSelection
+	skipHeader bool
+	writer     io.Writer
+}
+
+// NewCSV creates a new [CSV] renderer that will write to the provided [io.Writer]. The renderer can be configured using
+// the optional [CSVOptionFunc] arguments.
+func NewCSV(w io.Writer, opts ...CSVOptionFunc) *CSV {
+	c := &CSV{
+		selection: rowSelection{wide: true},
+		writer:    w,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// Render writes the passed data as comma-separated values. The data is handled the same way as in [Table.Render],
+// which means that it needs to be a slice of structs, or a slice of string slices. Columns with the `wide:"true"` tag
+// are always included, unless the columns are selected using [CSVWithColumns]. Values are quoted when needed, and are
+// otherwise written as is, including text that looks like inline color tags.
+func (c *CSV) Render(data any) error {
+	return renderDelimited(c.writer, ',', data, c.selection, c.skipHeader)
+}
+
+// TSVOptionFunc is a function that can be used to configure the [TSV] renderer.
+type TSVOptionFunc func(*TSV)
+
+// TSVWithShowHiddenColumns can be used to force rendering all exported fields in a struct, even if the field have the
+// `hidden:"true"` tag.
+func TSVWithShowHiddenColumns() TSVOptionFunc {
+	return func(t *TSV) {
+		t.selection.showHidden = true
+	}
+}
+
+// TSVWithColumns can be used to only write the columns with the provided headings, in the provided order. See
+// [TableWithColumns] for details.
+func TSVWithColumns(headings ...string) TSVOptionFunc {
+	return func(t *TSV) {
+		t.selection.columns = headings
+	}
+}
+
+// TSVWithSortBy can be used to sort the rows by one or more columns, identified by their headings. See
+// [TableWithSortBy] for details.
+func TSVWithSortBy(headings ...string) TSVOptionFunc {
+	return func(t *TSV) {
+		t.selection.sortBy = headings
+	}
+}
+
+// TSVWithFilters can be used to only write rows matching all the provided filters. See [TableWithFilters] for details.
+func TSVWithFilters(filters ...string) TSVOptionFunc {
+	return func(t *TSV) {
+		t.selection.filters = filters
+	}
+}
+
+// TSVWithoutHeader can be used to skip the header row.
+func TSVWithoutHeader() TSVOptionFunc {
+	return func(t *TSV) {
+		t.skipHeader = true
+	}
+}
+
+// TSV is a renderer that writes tab-separated values to an [io.Writer]. Use [NewTSV] to construct one.
+type TSV struct {
+	selection  rowSelection
+	skipHeader bool
+	writer     io.Writer
+}
+
+// NewTSV creates a new [TSV] renderer that will write to the provided [io.Writer]. The renderer can be configured using
+// the optional [TSVOptionFunc] arguments.
+func NewTSV(w io.Writer, opts ...TSVOptionFunc) *TSV {
+	t := &TSV{
+		selection: rowSelection{wide: true},
+		writer:    w,
+	}
+
+	for _, opt := range opts {
+		opt(t)
+	}
+
+	return t
+}
+
+// Render writes the passed data as tab-separated values. The data is handled the same way as in [Table.Render], which
+// means that it needs to be a slice of structs, or a slice of string slices. Columns with the `wide:"true"` tag are
+// always included, unless the columns are selected using [TSVWithColumns]. Values are quoted when needed, and are
+// otherwise written as is, including text that looks like inline color tags.
+func (t *TSV) Render(data any) error {
+	return renderDelimited(t.writer, '\t', data, t.selection, t.skipHeader)
+}
+
+// renderDelimited writes the rows extracted from data using the selection to w, separating the values with the
+// provided delimiter.
+func renderDelimited(w io.Writer, delimiter rune, data any, sel rowSelection, skipHeader bool) error {
+	_, rows, err := extractRows(data, sel)
+	if err != nil {
+		return err
+	}
+
+	if skipHeader {
+		rows = rows[1:]
+	}
+
+	cw := csv.NewWriter(w)
+	cw.Comma = delimiter
+	for _, row := range rows {
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,95 @@
+package output_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/nais/naistrix/output"
+)
+
+func TestCSV(t *testing.T) {
+	type user struct {
+		Name    string `heading:"Full Name"`
+		Comment string
+		Age     int `hidden:"true"`
+	}
+
+	users := []user{
+		{Name: "Alice", Comment: "likes <info>commas</info>, and \"quotes\"", Age: 30},
+		{Name: "Bob", Comment: "tab\tseparated", Age: 25},
+	}
+
+	tests := []struct {
+		name     string
+		render   func(*bytes.Buffer) error
+		expected string
+	}{
+		{
+			name: "csv from structs",
+			render: func(buf *bytes.Buffer) error {
+				return output.NewCSV(buf).Render(users)
+			},
+			expected: "Full Name,Comment\nAlice,\"likes <info>commas</info>, and \"\"quotes\"\"\"\nBob,tab\tseparated\n",
+		},
+		{
+			name: "csv with hidden columns and without header",
+			render: func(buf *bytes.Buffer) error {
+				return output.NewCSV(buf, output.CSVWithShowHiddenColumns(), output.CSVWithoutHeader()).Render(users)
+			},
+			expected: "Alice,\"likes <info>commas</info>, and \"\"quotes\"\"\",30\nBob,tab\tseparated,25\n",
+		},
+		{
+			name: "csv with selected columns, sorting and filters",
+			render: func(buf *bytes.Buffer) error {
+				return output.NewCSV(
+					buf,
+					output.CSVWithColumns("age", "full name"),
+					output.CSVWithSortBy("age"),
+					output.CSVWithFilters("age!=40"),
+				).Render(append(users, user{Name: "Carol", Age: 40}))
+			},
+			expected: "Age,Full Name\n25,Bob\n30,Alice\n",
+		},
+		{
+			name: "tsv from structs",
+			render: func(buf *bytes.Buffer) error {
+				return output.NewTSV(buf).Render(users)
+			},
+			expected: "Full Name\tComment\nAlice\t\"likes <info>commas</info>, and \"\"quotes\"\"\"\nBob\t\"tab\tseparated\"\n",
+		},
+		{
+			name: "tsv from string slices",
+			render: func(buf *bytes.Buffer) error {
+				return output.NewTSV(buf, output.TSVWithoutHeader()).Render([][]string{{"Name", "Age"}, {"Alice", "30"}})
+			},
+			expected: "Alice\t30\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := tt.render(&buf); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if actual := buf.String(); actual != tt.expected {
+				t.Fatalf("expected %q, got: %q", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestCSV_InvalidData(t *testing.T) {
+	var buf bytes.Buffer
+	err := output.NewCSV(&buf).Render("some data")
+
+	if err == nil {
+		t.Fatal("expected error")
+	}
+
+	if contains := "non-empty slice"; !strings.Contains(err.Error(), contains) {
+		t.Fatalf("expected error to contain %q, got: %v", contains, err)
+	}
+}
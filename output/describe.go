@@ -0,0 +1,205 @@
+package output
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// describeIndent is the indentation of each level of nested values.
+const describeIndent = "  "
+
+// describeNone is the value shown for nil values, and empty slices and maps.
+const describeNone = "<none>"
+
+// DescribeOptionFunc is a function that can be used to configure the [Describe] renderer.
+type DescribeOptionFunc func(*Describe)
+
+// DescribeWithShowHiddenFields can be used to render all exported fields in a struct, even if the field has the
+// `hidden:"true"` tag.
+func DescribeWithShowHiddenFields() DescribeOptionFunc {
+	return func(d *Describe) {
+		d.showHidden = true
+	}
+}
+
+// Describe is a renderer that writes the fields of a single object as aligned "Key: value" lines to an [io.Writer],
+// like for instance the details of a single application. Use [NewDescribe] to construct one.
+type Describe struct {
+	showHidden bool
+	writer     io.Writer
+}
+
+// NewDescribe creates a new [Describe] renderer that will write to the provided [io.Writer]. The renderer can be
+// configured using the optional [DescribeOptionFunc] arguments.
+func NewDescribe(w io.Writer, opts ...DescribeOptionFunc) *Describe {
+	d := &Describe{
+		writer: w,
+	}
+
+	for _, opt := range opts {
+		opt(d)
+	}
+
+	return d
+}
+
+// describeField is a single key and value rendered by the [Describe] renderer.
+type describeField struct {
+	key   string
+	value reflect.Value
+}
+
+// Render renders v, which must be a struct or a map, as aligned "Key: value" lines.
+//
+// All exported fields of a struct are rendered, using the field names as keys. Like with [Table], the keys can be
+// overridden using a `heading` field tag, and fields can be hidden using a `hidden` field tag set to "true". To show
+// hidden fields, use the [DescribeWithShowHiddenFields] option. The entries of a map are rendered sorted by key.
+//
+// Nested structs and maps are rendered below their key with indentation, and the elements of slices are rendered as a
+// list. Values implementing [fmt.Stringer], like [time.Time] and [Link], are rendered using their string
+// representation, also when String() has a pointer receiver. Nil values, and empty slices and maps are rendered as
+// "<none>". The values are data, so text in the values that looks like inline color tags is rendered as is.
+func (d *Describe) Render(v any) error {
+	rv := indirect(reflect.ValueOf(v))
+	if !rv.IsValid() || (rv.Kind() != reflect.Struct && rv.Kind() != reflect.Map) {
+		return fmt.Errorf("describe: expected a struct or a map, got %T", v)
+	}
+
+	// copy structs passed by value, so fields with a pointer receiver String() method are rendered like in tables
+	if !rv.CanAddr() {
+		cp := reflect.New(rv.Type()).Elem()
+		cp.Set(rv)
+		rv = cp
+	}
+
+	var sb strings.Builder
+	d.writeFields(&sb, d.fields(rv), "")
+
+	_, err := io.WriteString(d.writer, sb.String())
+	return err
+}
+
+// fields returns the keys and values of the struct or map rv.
+func (d *Describe) fields(rv reflect.Value) []describeField {
+	fields := make([]describeField, 0)
+
+	if rv.Kind() == reflect.Map {
+		for iter := rv.MapRange(); iter.Next(); {
+			fields = append(fields, describeField{key: fmt.Sprint(iter.Key().Interface()), value: iter.Value()})
+		}
+		slices.SortFunc(fields, func(a, b describeField) int {
+			return cmp.Compare(a.key, b.key)
+		})
+		return fields
+	}
+
+	for i := range rv.NumField() {
+		field := rv.Type().Field(i)
+		if !field.IsExported() || (field.Tag.Get("hidden") == "true" && !d.showHidden) {
+			continue
+		}
+
+		key := field.Name
+		if tag := field.Tag.Get("heading"); tag != "" {
+			key = tag
+		}
+
+		fields = append(fields, describeField{key: key, value: rv.Field(i)})
+	}
+
+	return fields
+}
+
+// writeFields writes aligned "Key: value" lines for the fields to sb, with each line prefixed by indent.
+func (d *Describe) writeFields(sb *strings.Builder, fields []describeField, indent string) {
+	width := 0
+	for _, f := range fields {
+		width = max(width, runewidth.StringWidth(f.key)+1)
+	}
+
+	for _, f := range fields {
+		v := indirect(f.value)
+		if isDescribeScalar(v) {
+			key := runewidth.FillRight(f.key+":", width) + " "
+			sb.WriteString(indent + key)
+			writeDescribeScalar(sb, v, indent+strings.Repeat(" ", width+1))
+			continue
+		}
+
+		sb.WriteString(indent + f.key + ":\n")
+		d.writeBlock(sb, v, indent+describeIndent)
+	}
+}
+
+// writeBlock writes a struct, map or slice value to sb, with each line prefixed by indent.
+func (d *Describe) writeBlock(sb *strings.Builder, v reflect.Value, indent string) {
+	if v.Kind() == reflect.Struct || v.Kind() == reflect.Map {
+		d.writeFields(sb, d.fields(v), indent)
+		return
+	}
+
+	for i := range v.Len() {
+		elem := indirect(v.Index(i))
+		if isDescribeScalar(elem) {
+			sb.WriteString(indent + "- ")
+			writeDescribeScalar(sb, elem, indent+describeIndent)
+			continue
+		}
+
+		// write the element without indentation, and prefix the first line with a list marker
+		var nested strings.Builder
+		d.writeBlock(&nested, elem, "")
+		for j, line := range strings.Split(strings.TrimSuffix(nested.String(), "\n"), "\n") {
+			if j == 0 {
+				sb.WriteString(indent + "- " + line + "\n")
+			} else {
+				sb.WriteString(indent + describeIndent + line + "\n")
+			}
+		}
+	}
+}
+
+// isDescribeScalar reports whether v is rendered on a single line, or on the same line as the key. This is the case
+// for all values that are not structs, maps or slices, for values implementing [fmt.Stringer], for nil values, and for
+// empty structs, maps and slices.
+func isDescribeScalar(v reflect.Value) bool {
+	if !v.IsValid() {
+		return true
+	}
+
+	if _, ok := asStringer(v); ok {
+		return true
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		return v.NumField() == 0
+	case reflect.Map, reflect.Slice, reflect.Array:
+		return v.Len() == 0
+	default:
+		return true
+	}
+}
+
+// writeDescribeScalar writes the string representation of v to sb, followed by a newline. Lines after the first line
+// of multi-line values are prefixed by indent.
+func writeDescribeScalar(sb *strings.Builder, v reflect.Value, indent string) {
+	s := describeNone
+	switch {
+	case !v.IsValid():
+	case v.Kind() == reflect.Map || v.Kind() == reflect.Slice || v.Kind() == reflect.Array:
+		if v.Len() > 0 {
+			s = getStringValue(v)
+		}
+	default:
+		s = getStringValue(v)
+	}
+
+	sb.WriteString(strings.ReplaceAll(s, "\n", "\n"+indent) + "\n")
+}
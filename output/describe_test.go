@@ -0,0 +1,130 @@
+package output_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/nais/naistrix/output"
+	"github.com/pterm/pterm"
+)
+
+type describePort struct {
+	Name string
+	Port int
+}
+
+type describeApp struct {
+	Name     string
+	Team     string `heading:"Team slug"`
+	Replicas *int
+	Secret   string `hidden:"true"`
+	Status   string
+	Image    struct {
+		Name string
+		Tag  string
+	}
+	Labels      map[string]string
+	Ports       []describePort
+	Ingresses   []string
+	Annotations map[string]string
+	Notes       string
+}
+
+func TestDescribe(t *testing.T) {
+	pterm.DisableStyling()
+	defer pterm.EnableStyling()
+
+	app := describeApp{
+		Name:     "api",
+		Team:     "team-a",
+		Replicas: new(2),
+		Secret:   "s3cr3t",
+		Status:   "<error>failing</error>",
+		Labels:   map[string]string{"team": "team-a", "app": "api"},
+		Ports: []describePort{
+			{Name: "http", Port: 8080},
+			{Name: "metrics", Port: 9090},
+		},
+		Ingresses: []string{"https://api.example.com", "https://api.internal"},
+		Notes:     "first line\nsecond line",
+	}
+	app.Image.Name = "ghcr.io/nais/api"
+	app.Image.Tag = "v1"
+
+	tests := []struct {
+		name     string
+		data     any
+		opts     []output.DescribeOptionFunc
+		expected string
+	}{
+		{
+			name: "struct",
+			data: &app,
+			expected: "Name:        api\n" +
+				"Team slug:   team-a\n" +
+				"Replicas:    2\n" +
+				"Status:      <error>failing</error>\n" +
+				"Image:\n" +
+				"  Name: ghcr.io/nais/api\n" +
+				"  Tag:  v1\n" +
+				"Labels:\n" +
+				"  app:  api\n" +
+				"  team: team-a\n" +
+				"Ports:\n" +
+				"  - Name: http\n" +
+				"    Port: 8080\n" +
+				"  - Name: metrics\n" +
+				"    Port: 9090\n" +
+				"Ingresses:\n" +
+				"  - https://api.example.com\n" +
+				"  - https://api.internal\n" +
+				"Annotations: <none>\n" +
+				"Notes:       first line\n" +
+				"             second line\n",
+		},
+		{
+			name: "hidden fields",
+			data: struct {
+				Name   string
+				Secret string `hidden:"true"`
+			}{Name: "api", Secret: "s3cr3t"},
+			opts:     []output.DescribeOptionFunc{output.DescribeWithShowHiddenFields()},
+			expected: "Name:   api\nSecret: s3cr3t\n",
+		},
+		{
+			name: "stringer with a pointer receiver",
+			data: struct {
+				Name    string
+				Version version
+			}{Name: "api", Version: version{major: 1, minor: 2}},
+			expected: "Name:    api\nVersion: v1.2\n",
+		},
+		{
+			name:     "map",
+			data:     map[string]any{"b": 2, "a": nil, "c": []int{1}},
+			expected: "a: <none>\nb: 2\nc:\n  - 1\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := output.NewDescribe(&buf, tt.opts...).Render(tt.data); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if actual := buf.String(); actual != tt.expected {
+				t.Errorf("expected:\n%s\ngot:\n%s", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestDescribe_InvalidData(t *testing.T) {
+	for _, data := range []any{nil, "some data", []string{"a"}} {
+		var buf bytes.Buffer
+		if err := output.NewDescribe(&buf).Render(data); err == nil {
+			t.Errorf("expected error for %#v", data)
+		}
+	}
+}
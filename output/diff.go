@@ -0,0 +1,299 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/pterm/pterm"
+	"gopkg.in/yaml.v3"
+)
+
+// Change holds two versions of a value, and is rendered as the differences between the two by the [Diff] renderer.
+type Change struct {
+	// Before is the value before the change. A nil value is rendered as if every line was added.
+	Before any
+
+	// After is the value after the change. A nil value is rendered as if every line was removed.
+	After any
+}
+
+// DiffOptionFunc is a function that can be used to configure the [Diff] renderer.
+type DiffOptionFunc func(*Diff)
+
+// DiffWithLabels can be used to set the labels of the two values in the header of the diff, for instance the name of a
+// file. The labels default to "before" and "after".
+func DiffWithLabels(before, after string) DiffOptionFunc {
+	return func(d *Diff) {
+		d.beforeLabel = before
+		d.afterLabel = after
+	}
+}
+
+// DiffWithContext can be used to set the number of unchanged lines shown around each change, which defaults to 3.
+func DiffWithContext(lines int) DiffOptionFunc {
+	return func(d *Diff) {
+		d.context = max(lines, 0)
+	}
+}
+
+// Diff is a renderer that encodes two versions of a value as YAML, and writes the differences between them as a unified
+// diff to an [io.Writer]. Use [NewDiff] to construct one.
+type Diff struct {
+	beforeLabel string
+	afterLabel  string
+	context     int
+	writer      io.Writer
+}
+
+// NewDiff creates a new [Diff] renderer that will write to the provided [io.Writer]. The renderer can be configured
+// using the optional [DiffOptionFunc] arguments.
+func NewDiff(w io.Writer, opts ...DiffOptionFunc) *Diff {
+	d := &Diff{
+		beforeLabel: "before",
+		afterLabel:  "after",
+		context:     3,
+		writer:      w,
+	}
+
+	for _, opt := range opts {
+		opt(d)
+	}
+
+	return d
+}
+
+// Render renders the differences between the two values of v, which must be a [Change]. See [Diff.RenderChange] for
+// details.
+func (d *Diff) Render(v any) error {
+	switch c := v.(type) {
+	case Change:
+		return d.RenderChange(c.Before, c.After)
+	case *Change:
+		return d.RenderChange(c.Before, c.After)
+	default:
+		return fmt.Errorf("diff: expected an output.Change, got %T", v)
+	}
+}
+
+// RenderChange encodes before and after as YAML, and renders the differences between them as a unified diff. Removed
+// lines are colored red and added lines green, unless styling is disabled. Nothing is written when the values are
+// equal. Very large changes are rendered as all the changed lines being removed, followed by all of them being added.
+func (d *Diff) RenderChange(before, after any) error {
+	a, err := diffLines(before)
+	if err != nil {
+		return err
+	}
+
+	b, err := diffLines(after)
+	if err != nil {
+		return err
+	}
+
+	hunks := diffHunks(diffOps(a, b), d.context)
+	if len(hunks) == 0 {
+		return nil
+	}
+
+	var sb strings.Builder
+	sb.WriteString(diffStyle(pterm.Bold, "--- "+d.beforeLabel) + "\n")
+	sb.WriteString(diffStyle(pterm.Bold, "+++ "+d.afterLabel) + "\n")
+
+	for _, h := range hunks {
+		sb.WriteString(diffStyle(pterm.FgCyan, h.header()) + "\n")
+		for _, op := range h.ops {
+			line := string(op.kind) + op.line
+			switch op.kind {
+			case diffDelete:
+				line = diffStyle(pterm.FgRed, line)
+			case diffInsert:
+				line = diffStyle(pterm.FgGreen, line)
+			}
+			sb.WriteString(line + "\n")
+		}
+	}
+
+	_, err = io.WriteString(d.writer, sb.String())
+	return err
+}
+
+// diffStyle applies the color to s, unless styling is disabled.
+func diffStyle(c pterm.Color, s string) string {
+	if pterm.RawOutput {
+		return s
+	}
+	return c.Sprint(s)
+}
+
+// diffLines encodes v as YAML, and returns the lines of the result. A nil value has no lines.
+func diffLines(v any) ([]string, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	b, err := yaml.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return strings.Split(strings.TrimSuffix(string(b), "\n"), "\n"), nil
+}
+
+// Kinds of lines in a diff.
+const (
+	diffEqual  = ' '
+	diffDelete = '-'
+	diffInsert = '+'
+)
+
+// diffOp is a single line in a diff. The before and after fields hold the number of lines of each value preceding
+// the line.
+type diffOp struct {
+	kind   byte
+	line   string
+	before int
+	after  int
+}
+
+// maxDiffCells is the maximum size of the table used to find the longest common subsequence of the changed lines,
+// which grows with the product of the number of changed lines in each value. Larger changes are rendered as if all the
+// changed lines were removed and then added, which keeps the memory used by large diffs bounded.
+const maxDiffCells = 1 << 20
+
+// diffOps returns the operations needed to turn the lines of a into the lines of b. The lines the two have in common at
+// the start and the end are kept as is, and the lines in between are diffed based on their longest common subsequence.
+func diffOps(a, b []string) []diffOp {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	ops := make([]diffOp, 0, len(a)+len(b))
+	for i := range prefix {
+		ops = append(ops, diffOp{kind: diffEqual, line: a[i], before: i, after: i})
+	}
+
+	ops = append(ops, lcsOps(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix], prefix)...)
+
+	for k := suffix; k > 0; k-- {
+		ops = append(ops, diffOp{kind: diffEqual, line: a[len(a)-k], before: len(a) - k, after: len(b) - k})
+	}
+
+	return ops
+}
+
+// lcsOps returns the operations needed to turn the lines of a into the lines of b, based on the longest common
+// subsequence of the two. The lines are preceded by offset lines in both values. When the longest common subsequence
+// would be too expensive to find, see [maxDiffCells], all lines of a are removed and all lines of b are added.
+func lcsOps(a, b []string, offset int) []diffOp {
+	ops := make([]diffOp, 0, len(a)+len(b))
+	if (len(a)+1)*(len(b)+1) > maxDiffCells {
+		for i, line := range a {
+			ops = append(ops, diffOp{kind: diffDelete, line: line, before: offset + i, after: offset})
+		}
+		for j, line := range b {
+			ops = append(ops, diffOp{kind: diffInsert, line: line, before: offset + len(a), after: offset + j})
+		}
+		return ops
+	}
+
+	// lcs[i][j] holds the length of the longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			ops = append(ops, diffOp{kind: diffEqual, line: a[i], before: offset + i, after: offset + j})
+			i++
+			j++
+		case j == len(b) || (i < len(a) && lcs[i+1][j] >= lcs[i][j+1]):
+			ops = append(ops, diffOp{kind: diffDelete, line: a[i], before: offset + i, after: offset + j})
+			i++
+		default:
+			ops = append(ops, diffOp{kind: diffInsert, line: b[j], before: offset + i, after: offset + j})
+			j++
+		}
+	}
+
+	return ops
+}
+
+// diffHunk is a group of changed lines, with the unchanged lines around them.
+type diffHunk struct {
+	ops []diffOp
+}
+
+// diffHunks groups the changes in ops into hunks, with up to context unchanged lines around each change. Changes that
+// are separated by less than twice the number of context lines are placed in the same hunk.
+func diffHunks(ops []diffOp, context int) []diffHunk {
+	hunks := make([]diffHunk, 0)
+	start, end := -1, -1
+	for i, op := range ops {
+		if op.kind == diffEqual {
+			continue
+		}
+
+		if start >= 0 && i-end > 2*context+1 {
+			hunks = append(hunks, diffHunk{ops: ops[max(start-context, 0):min(end+context+1, len(ops))]})
+			start = -1
+		}
+
+		if start < 0 {
+			start = i
+		}
+		end = i
+	}
+
+	if start >= 0 {
+		hunks = append(hunks, diffHunk{ops: ops[max(start-context, 0):min(end+context+1, len(ops))]})
+	}
+
+	return hunks
+}
+
+// header returns the "@@ -l,s +l,s @@" line of the hunk, with the line numbers and number of lines of each value.
+func (h diffHunk) header() string {
+	before, after := 0, 0
+	for _, op := range h.ops {
+		if op.kind != diffInsert {
+			before++
+		}
+		if op.kind != diffDelete {
+			after++
+		}
+	}
+
+	return fmt.Sprintf("@@ -%s +%s @@", diffRange(h.ops[0].before, before), diffRange(h.ops[0].after, after))
+}
+
+// diffRange formats the range of a hunk, where start is the number of lines preceding the hunk. Like in GNU diff, the
+// number of lines is left out when it is one, and an empty range starts at the line preceding the hunk.
+func diffRange(start, lines int) string {
+	switch lines {
+	case 0:
+		return fmt.Sprintf("%d,0", start)
+	case 1:
+		return fmt.Sprintf("%d", start+1)
+	default:
+		return fmt.Sprintf("%d,%d", start+1, lines)
+	}
+}
@@ -0,0 +1,173 @@
+package output_test
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/nais/naistrix/output"
+	"github.com/pterm/pterm"
+)
+
+func TestDiff(t *testing.T) {
+	pterm.DisableStyling()
+	defer pterm.EnableStyling()
+
+	type config struct {
+		Team    string   `yaml:"team"`
+		Cluster string   `yaml:"cluster"`
+		Tags    []string `yaml:"tags"`
+	}
+
+	tests := []struct {
+		name     string
+		change   any
+		opts     []output.DiffOptionFunc
+		expected string
+	}{
+		{
+			name: "changed value",
+			change: output.Change{
+				Before: config{Team: "team-a", Cluster: "dev", Tags: []string{"a", "b"}},
+				After:  config{Team: "team-a", Cluster: "prod", Tags: []string{"a", "b", "c"}},
+			},
+			expected: "--- before\n+++ after\n" +
+				"@@ -1,5 +1,6 @@\n" +
+				" team: team-a\n" +
+				"-cluster: dev\n" +
+				"+cluster: prod\n" +
+				" tags:\n" +
+				"     - a\n" +
+				"     - b\n" +
+				"+    - c\n",
+		},
+		{
+			name: "labels and context",
+			change: &output.Change{
+				Before: map[string]int{"a": 1, "b": 2, "c": 3, "d": 4, "e": 5, "f": 6},
+				After:  map[string]int{"a": 0, "b": 2, "c": 3, "d": 4, "e": 5, "f": 7},
+			},
+			opts: []output.DiffOptionFunc{output.DiffWithLabels("config.yaml", "config.yaml"), output.DiffWithContext(1)},
+			expected: "--- config.yaml\n+++ config.yaml\n" +
+				"@@ -1,2 +1,2 @@\n" +
+				"-a: 1\n" +
+				"+a: 0\n" +
+				" b: 2\n" +
+				"@@ -5,2 +5,2 @@\n" +
+				" e: 5\n" +
+				"-f: 6\n" +
+				"+f: 7\n",
+		},
+		{
+			name:     "added",
+			change:   output.Change{After: map[string]string{"key": "value"}},
+			expected: "--- before\n+++ after\n@@ -0,0 +1 @@\n+key: value\n",
+		},
+		{
+			name:     "removed",
+			change:   output.Change{Before: map[string]string{"key": "value"}},
+			expected: "--- before\n+++ after\n@@ -1 +0,0 @@\n-key: value\n",
+		},
+		{
+			name:     "equal",
+			change:   output.Change{Before: map[string]string{"key": "value"}, After: map[string]string{"key": "value"}},
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := output.NewDiff(&buf, tt.opts...).Render(tt.change); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if actual := buf.String(); actual != tt.expected {
+				t.Errorf("expected:\n%s\ngot:\n%s", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestDiff_Large(t *testing.T) {
+	pterm.DisableStyling()
+	defer pterm.EnableStyling()
+
+	lines := func(n int, prefix string) []string {
+		ret := make([]string, n)
+		for i := range ret {
+			ret[i] = fmt.Sprintf("%s-%d", prefix, i)
+		}
+		return ret
+	}
+
+	t.Run("single change", func(t *testing.T) {
+		before := lines(5000, "line")
+		after := slices.Clone(before)
+		after[2500] = "changed"
+
+		var buf bytes.Buffer
+		if err := output.NewDiff(&buf).RenderChange(before, after); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		expected := "--- before\n+++ after\n" +
+			"@@ -2498,7 +2498,7 @@\n" +
+			" - line-2497\n - line-2498\n - line-2499\n" +
+			"-- line-2500\n" +
+			"+- changed\n" +
+			" - line-2501\n - line-2502\n - line-2503\n"
+		if actual := buf.String(); actual != expected {
+			t.Errorf("expected:\n%s\ngot:\n%s", expected, actual)
+		}
+	})
+
+	t.Run("everything changed", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := output.NewDiff(&buf).RenderChange(lines(2000, "before"), lines(2000, "after")); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		actual := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+		if header := actual[2]; header != "@@ -1,2000 +1,2000 @@" {
+			t.Fatalf("expected a single hunk with all lines, got header %q", header)
+		}
+
+		if len(actual) != 4003 {
+			t.Fatalf("expected all lines to be removed and added, got %d lines", len(actual))
+		}
+
+		for i, line := range actual[3:] {
+			prefix := "-- before-"
+			if i >= 2000 {
+				prefix = "+- after-"
+			}
+
+			if !strings.HasPrefix(line, prefix) {
+				t.Fatalf("expected line %d to start with %q, got %q", i, prefix, line)
+			}
+		}
+	})
+}
+
+func TestDiff_Colors(t *testing.T) {
+	var buf bytes.Buffer
+	if err := output.NewDiff(&buf).RenderChange("a", "b"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := "\x1b[1m--- before\x1b[0m\n\x1b[1m+++ after\x1b[0m\n\x1b[36m@@ -1 +1 @@\x1b[0m\n" +
+		"\x1b[31m-a\x1b[0m\n\x1b[32m+b\x1b[0m\n"
+	if actual := buf.String(); actual != expected {
+		t.Errorf("expected %q, got %q", expected, actual)
+	}
+}
+
+func TestDiff_InvalidData(t *testing.T) {
+	var buf bytes.Buffer
+	if err := output.NewDiff(&buf).Render("some data"); err == nil {
+		t.Fatal("expected error")
+	}
+}
@@ -25,10 +25,20 @@ func JSONWithIndentChar(indent string) JSONOptionFunc {
 	}
 }
 
+// JSONWithQuery can be used to render only the parts of the value selected by a query expression. See [Query] for the
+// supported syntax. Queries that can select more than one value, for instance by using wildcards, render a list of all
+// selected values. An empty expression renders the whole value.
+func JSONWithQuery(expression string) JSONOptionFunc {
+	return func(j *JSON) {
+		j.query = expression
+	}
+}
+
 // JSON is a renderer that encodes values as JSON and writes them to an [io.Writer]. Use [NewJSON] to construct one.
 type JSON struct {
 	prettify   bool
 	indentChar string
+	query      string
 	writer     io.Writer
 }
 
@@ -47,8 +57,34 @@ func NewJSON(w io.Writer, opts ...JSONOptionFunc) *JSON {
 	return j
 }
 
-// Render encodes v as JSON and writes the result to the configured [io.Writer].
+// Validate checks that the query expression set with [JSONWithQuery] is valid, and returns a [*QueryError] if not. This
+// method satisfies the [Validator] interface.
+func (j *JSON) Validate() error {
+	if j.query == "" {
+		return nil
+	}
+
+	_, err := ParseQuery(j.query)
+	return err
+}
+
+// Render encodes v as JSON and writes the result to the configured [io.Writer]. If the query expression set with
+// [JSONWithQuery] is invalid, a [*QueryError] is returned.
 func (j *JSON) Render(v any) error {
+	if j.query != "" {
+		q, err := ParseQuery(j.query)
+		if err != nil {
+			return err
+		}
+
+		doc, err := normalizeJSON(v)
+		if err != nil {
+			return err
+		}
+
+		v = q.result(doc)
+	}
+
 	enc := json.NewEncoder(j.writer)
 	if j.prettify {
 		enc.SetIndent("", j.indentChar)
@@ -0,0 +1,159 @@
+package output
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+)
+
+// JSONPath is a renderer that writes the values selected by a JSONPath template as plain text, similar to the jsonpath
+// output format in kubectl. Use [NewJSONPath] to construct one.
+type JSONPath struct {
+	template string
+	writer   io.Writer
+}
+
+// NewJSONPath creates a new [JSONPath] renderer that will write to the provided [io.Writer], using the provided
+// template.
+//
+// The template consists of plain text and query expressions wrapped in braces, like for instance "Name: {.name}". See
+// [Query] for the supported query syntax. Text inside braces can be added as a quoted string, like {"\n"}. A template
+// without any braces is treated as a single query expression.
+func NewJSONPath(w io.Writer, template string) *JSONPath {
+	return &JSONPath{
+		template: template,
+		writer:   w,
+	}
+}
+
+// jsonPathSegment is either plain text or a query in a JSONPath template.
+type jsonPathSegment struct {
+	text  string
+	query *Query
+}
+
+// Validate checks that the template is valid, and returns a [*QueryError] if not. This method satisfies the [Validator]
+// interface.
+func (j *JSONPath) Validate() error {
+	_, err := parseJSONPathTemplate(j.template)
+	return err
+}
+
+// Render executes the template against v, and writes the result to the configured [io.Writer]. Selected strings are
+// written as is, while other values are written as JSON. When a query selects more than one value, the values are
+// separated by spaces. A newline is added to the end of the output, unless the output already ends with a newline. If
+// the template is invalid, a [*QueryError] is returned.
+func (j *JSONPath) Render(v any) error {
+	segments, err := parseJSONPathTemplate(j.template)
+	if err != nil {
+		return err
+	}
+
+	doc, err := normalizeJSON(v)
+	if err != nil {
+		return err
+	}
+
+	var sb strings.Builder
+	for _, s := range segments {
+		if s.query == nil {
+			sb.WriteString(s.text)
+			continue
+		}
+
+		for i, result := range s.query.execute(doc) {
+			if i > 0 {
+				sb.WriteByte(' ')
+			}
+
+			if str, ok := result.(string); ok {
+				sb.WriteString(str)
+				continue
+			}
+
+			b, err := json.Marshal(result)
+			if err != nil {
+				return err
+			}
+			sb.Write(b)
+		}
+	}
+
+	out := sb.String()
+	if !strings.HasSuffix(out, "\n") {
+		out += "\n"
+	}
+
+	_, err = io.WriteString(j.writer, out)
+	return err
+}
+
+// parseJSONPathTemplate splits a JSONPath template into plain text and query segments.
+func parseJSONPathTemplate(template string) ([]jsonPathSegment, error) {
+	if !strings.Contains(template, "{") {
+		q, err := ParseQuery(template)
+		if err != nil {
+			return nil, err
+		}
+		return []jsonPathSegment{{query: q}}, nil
+	}
+
+	segments := make([]jsonPathSegment, 0)
+	for pos := 0; pos < len(template); {
+		start := strings.IndexByte(template[pos:], '{')
+		if start < 0 {
+			segments = append(segments, jsonPathSegment{text: template[pos:]})
+			break
+		}
+		start += pos
+
+		if start > pos {
+			segments = append(segments, jsonPathSegment{text: template[pos:start]})
+		}
+
+		end := closingBrace(template, start)
+		if end < 0 {
+			return nil, &QueryError{Expression: template, Position: start, Reason: "unclosed {"}
+		}
+
+		content := strings.TrimSpace(template[start+1 : end])
+		if len(content) > 0 && (content[0] == '"' || content[0] == '\'') {
+			p := &queryParser{expression: template, input: content, offset: start + 1}
+			text, err := p.parseString()
+			if err != nil {
+				return nil, err
+			}
+			segments = append(segments, jsonPathSegment{text: text})
+		} else {
+			q, err := ParseQuery(content)
+			if err != nil {
+				return nil, err
+			}
+			segments = append(segments, jsonPathSegment{query: q})
+		}
+
+		pos = end + 1
+	}
+
+	return segments, nil
+}
+
+// closingBrace returns the position of the brace that closes the brace at the start position, ignoring braces inside
+// quoted strings. If there is no closing brace -1 is returned.
+func closingBrace(s string, start int) int {
+	var quote byte
+	for i := start + 1; i < len(s); i++ {
+		switch c := s[i]; {
+		case quote != 0 && c == '\\':
+			i++
+		case quote != 0 && c == quote:
+			quote = 0
+		case quote != 0:
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '}':
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,223 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/pterm/pterm"
+)
+
+var (
+	// markdownHeading matches ATX headings, like "## Heading".
+	markdownHeading = regexp.MustCompile(`^\s{0,3}(#{1,6})\s+(.*?)(?:\s+#+)?\s*$`)
+
+	// markdownListItem matches items in unordered and ordered lists, like "- item" and "1. item".
+	markdownListItem = regexp.MustCompile(`^(\s*)([-*+]|\d+[.)])\s+(.*)$`)
+
+	// markdownQuote matches lines in block quotes, like "> quote".
+	markdownQuote = regexp.MustCompile(`^\s{0,3}>\s?(.*)$`)
+
+	// markdownCode matches inline code spans, like "`code`".
+	markdownCode = regexp.MustCompile("`([^`]+)`")
+
+	// markdownLink matches inline links, like "[text](url)", and autolinks, like "<https://example.com>".
+	markdownLink = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)\)|<(https?://[^>\s]+)>`)
+
+	// markdownStrong matches strong emphasis, like "**text**" and "__text__".
+	markdownStrong = regexp.MustCompile(`\*\*([^*]+)\*\*|\b__([^_]+)__\b`)
+
+	// markdownEmphasis matches emphasis, like "*text*" and "_text_".
+	markdownEmphasis = regexp.MustCompile(`\*([^*\s](?:[^*]*[^*\s])?)\*|\b_([^_\s](?:[^_]*[^_\s])?)_\b`)
+)
+
+// markdownCodeIndent is the indentation of the lines in code blocks.
+const markdownCodeIndent = "    "
+
+// markdownRuleWidth is the width of horizontal rules.
+const markdownRuleWidth = 40
+
+// Markdown is a renderer that formats markdown for the terminal, and writes the result to an [io.Writer]. Use
+// [NewMarkdown] to construct one.
+type Markdown struct {
+	writer io.Writer
+}
+
+// NewMarkdown creates a new [Markdown] renderer that will write to the provided [io.Writer].
+func NewMarkdown(w io.Writer) *Markdown {
+	return &Markdown{
+		writer: w,
+	}
+}
+
+// Render formats v, which must be a string or a byte slice holding markdown, and writes the result followed by a
+// newline.
+//
+// Headings and strong emphasis are rendered in bold, emphasis in italics, and inline code and code blocks in color.
+// Code blocks are indented, and the items of unordered lists are prefixed with bullets. Links are rendered as
+// clickable hyperlinks using [Link] when the terminal supports it, and as the text of the link followed by the URL
+// otherwise. Other markdown, like paragraphs, is written as is, without reflowing the text.
+//
+// When styling is disabled, the markup is removed, and the text is written without colors.
+func (m *Markdown) Render(v any) error {
+	var text string
+	switch t := v.(type) {
+	case string:
+		text = t
+	case []byte:
+		text = string(t)
+	default:
+		return fmt.Errorf("markdown: expected a string or a byte slice, got %T", v)
+	}
+
+	_, err := io.WriteString(m.writer, formatMarkdown(text)+"\n")
+	return err
+}
+
+// formatMarkdown formats the markdown in text for the terminal, and returns the result.
+func formatMarkdown(text string) string {
+	lines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+	ret := make([]string, 0, len(lines))
+
+	var fence string
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+				continue
+			}
+			ret = append(ret, markdownCodeIndent+markdownStyle(pterm.FgCyan, line))
+			continue
+		}
+
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			fence = trimmed[:3]
+			continue
+		}
+
+		if isMarkdownRule(trimmed) {
+			if pterm.RawOutput {
+				ret = append(ret, strings.Repeat("-", markdownRuleWidth))
+			} else {
+				ret = append(ret, markdownStyle(pterm.FgGray, strings.Repeat("─", markdownRuleWidth)))
+			}
+			continue
+		}
+
+		if m := markdownHeading.FindStringSubmatch(line); m != nil {
+			heading := markdownStyle(pterm.Bold, markdownInline(m[2]))
+			if len(m[1]) == 1 {
+				heading = markdownStyle(pterm.Underscore, heading)
+			}
+
+			ret = append(ret, heading)
+			continue
+		}
+
+		if m := markdownListItem.FindStringSubmatch(line); m != nil {
+			marker := m[2]
+			if strings.ContainsAny(marker, "-*+") {
+				marker = "•"
+				if pterm.RawOutput {
+					marker = "-"
+				}
+			}
+
+			ret = append(ret, m[1]+marker+" "+markdownInline(m[3]))
+			continue
+		}
+
+		if m := markdownQuote.FindStringSubmatch(line); m != nil {
+			if pterm.RawOutput {
+				ret = append(ret, "> "+markdownInline(m[1]))
+			} else {
+				ret = append(ret, markdownStyle(pterm.FgGray, "│ ")+markdownStyle(pterm.Italic, markdownInline(m[1])))
+			}
+			continue
+		}
+
+		ret = append(ret, markdownInline(line))
+	}
+
+	return strings.Join(ret, "\n")
+}
+
+// isMarkdownRule reports whether the trimmed line is a horizontal rule, like "---" or "* * *".
+func isMarkdownRule(trimmed string) bool {
+	s := strings.ReplaceAll(trimmed, " ", "")
+	if len(s) < 3 || !strings.ContainsAny(s[:1], "-*_") {
+		return false
+	}
+	return strings.Count(s, s[:1]) == len(s)
+}
+
+// markdownInline formats the inline markup of s, like code spans, links and emphasis.
+func markdownInline(s string) string {
+	return replaceMatches(s, markdownCode, func(m []string) string {
+		return markdownStyle(pterm.FgCyan, m[1])
+	}, func(s string) string {
+		return replaceMatches(s, markdownLink, markdownFormatLink, markdownEmphasize)
+	})
+}
+
+// markdownFormatLink formats a link matched by markdownLink.
+func markdownFormatLink(m []string) string {
+	text, url := markdownEmphasize(m[1]), m[2]
+	if m[3] != "" {
+		text, url = m[3], m[3]
+	}
+
+	if !pterm.RawOutput && supportsHyperlinks() {
+		return NewLink(markdownStyle(pterm.Underscore, text), url).String()
+	}
+
+	if text == url {
+		return url
+	}
+	return text + " (" + url + ")"
+}
+
+// markdownEmphasize formats strong emphasis and emphasis in s.
+func markdownEmphasize(s string) string {
+	s = markdownStrong.ReplaceAllStringFunc(s, func(match string) string {
+		m := markdownStrong.FindStringSubmatch(match)
+		return markdownStyle(pterm.Bold, m[1]+m[2])
+	})
+
+	return markdownEmphasis.ReplaceAllStringFunc(s, func(match string) string {
+		m := markdownEmphasis.FindStringSubmatch(match)
+		return markdownStyle(pterm.Italic, m[1]+m[2])
+	})
+}
+
+// replaceMatches replaces the matches of re in s with the result of match, and the text between the matches with the
+// result of rest.
+func replaceMatches(s string, re *regexp.Regexp, match func([]string) string, rest func(string) string) string {
+	var sb strings.Builder
+	last := 0
+	for _, loc := range re.FindAllStringSubmatchIndex(s, -1) {
+		sb.WriteString(rest(s[last:loc[0]]))
+
+		m := make([]string, len(loc)/2)
+		for i := range m {
+			if loc[2*i] >= 0 {
+				m[i] = s[loc[2*i]:loc[2*i+1]]
+			}
+		}
+		sb.WriteString(match(m))
+		last = loc[1]
+	}
+	sb.WriteString(rest(s[last:]))
+	return sb.String()
+}
+
+// markdownStyle applies the style to s, unless styling is disabled.
+func markdownStyle(style interface{ Sprint(...any) string }, s string) string {
+	if pterm.RawOutput {
+		return s
+	}
+	return style.Sprint(s)
+}
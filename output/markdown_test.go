@@ -0,0 +1,81 @@
+package output_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/nais/naistrix/output"
+	"github.com/pterm/pterm"
+)
+
+func TestMarkdown(t *testing.T) {
+	markdown := "# Deploy\n" +
+		"\n" +
+		"Deploys an application, see the [documentation](https://doc.nais.io) or <https://nais.io>.\n" +
+		"\n" +
+		"## Options\n" +
+		"\n" +
+		"- Use **--wait** to wait for the _rollout_ of `my_app`\n" +
+		"  * nested item with snake_case_names\n" +
+		"1. first\n" +
+		"\n" +
+		"> Note: this is *important*\n" +
+		"\n" +
+		"---\n" +
+		"```sh\n" +
+		"nais deploy --wait **not bold**\n" +
+		"```\n"
+
+	t.Run("without styling", func(t *testing.T) {
+		pterm.DisableStyling()
+		defer pterm.EnableStyling()
+
+		expected := "Deploy\n" +
+			"\n" +
+			"Deploys an application, see the documentation (https://doc.nais.io) or https://nais.io.\n" +
+			"\n" +
+			"Options\n" +
+			"\n" +
+			"- Use --wait to wait for the rollout of my_app\n" +
+			"  - nested item with snake_case_names\n" +
+			"1. first\n" +
+			"\n" +
+			"> Note: this is important\n" +
+			"\n" +
+			"----------------------------------------\n" +
+			"    nais deploy --wait **not bold**\n"
+
+		var buf bytes.Buffer
+		if err := output.NewMarkdown(&buf).Render(markdown); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if actual := buf.String(); actual != expected {
+			t.Errorf("expected:\n%s\ngot:\n%s", expected, actual)
+		}
+	})
+
+	t.Run("with styling", func(t *testing.T) {
+		expected := "\x1b[4m\x1b[1mTitle\x1b[0m\x1b[4m\x1b[0m\n" +
+			"\x1b[1mSection\x1b[0m\n" +
+			"• \x1b[1mbold\x1b[0m, \x1b[3mitalic\x1b[0m and \x1b[36mcode\x1b[0m\n" +
+			"    \x1b[36mcode block\x1b[0m\n"
+
+		var buf bytes.Buffer
+		err := output.NewMarkdown(&buf).Render([]byte("# Title\n## Section\n- **bold**, *italic* and `code`\n~~~\ncode block\n~~~"))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if actual := buf.String(); actual != expected {
+			t.Errorf("expected %q, got %q", expected, actual)
+		}
+	})
+}
+
+func TestMarkdown_InvalidData(t *testing.T) {
+	var buf bytes.Buffer
+	if err := output.NewMarkdown(&buf).Render(42); err == nil {
+		t.Fatal("expected error")
+	}
+}
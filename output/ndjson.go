@@ -0,0 +1,127 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// flusher is implemented by writers that buffer output, like for instance [bufio.Writer].
+type flusher interface {
+	Flush() error
+}
+
+// flush flushes w if it buffers output.
+func flush(w io.Writer) error {
+	if f, ok := w.(flusher); ok {
+		return f.Flush()
+	}
+	return nil
+}
+
+// NDJSON is a streaming renderer that encodes values as newline delimited JSON (also known as JSON lines) and writes
+// them to an [io.Writer], one value per line. Use [NewNDJSON] to construct one.
+type NDJSON struct {
+	writer  io.Writer
+	encoder *json.Encoder
+}
+
+// NewNDJSON creates a new [NDJSON] renderer that will write to the provided [io.Writer].
+func NewNDJSON(w io.Writer) *NDJSON {
+	return &NDJSON{
+		writer:  w,
+		encoder: json.NewEncoder(w),
+	}
+}
+
+// Write encodes a single value as a line of JSON and writes it to the configured [io.Writer]. If the writer buffers
+// output, it is flushed after the line has been written, so the line is available to the consumer immediately.
+func (n *NDJSON) Write(v any) error {
+	if err := n.encoder.Encode(v); err != nil {
+		return err
+	}
+
+	return flush(n.writer)
+}
+
+// Render writes each item in v as a separate line of JSON. The following values are supported:
+//
+//   - A slice or an array, where each element is written as a line.
+//   - An [iter.Seq], where each item is written as soon as it is yielded by the sequence.
+//   - An [iter.Seq2] where the second value is an error. Each item is written as soon as it is yielded, and the first
+//     non-nil error stops the rendering and is returned.
+//
+// Any other value is written as a single line.
+func (n *NDJSON) Render(v any) error {
+	rv := reflect.ValueOf(v)
+	switch {
+	case rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array:
+		for i := range rv.Len() {
+			if err := n.Write(rv.Index(i).Interface()); err != nil {
+				return err
+			}
+		}
+	case IsSeq(v):
+		for item := range rv.Seq() {
+			if err := n.Write(item.Interface()); err != nil {
+				return err
+			}
+		}
+	case IsSeq2(v):
+		for item, err := range rv.Seq2() {
+			if !err.IsNil() {
+				return err.Interface().(error)
+			}
+
+			if err := n.Write(item.Interface()); err != nil {
+				return err
+			}
+		}
+	case rv.Kind() == reflect.Func:
+		return fmt.Errorf("unsupported sequence type %T", v)
+	default:
+		return n.Write(v)
+	}
+
+	return nil
+}
+
+// IsSeq reports whether v is an [iter.Seq].
+func IsSeq(v any) bool {
+	t := reflect.TypeOf(v)
+	return t != nil && t.Kind() == reflect.Func && t.CanSeq()
+}
+
+// IsSeq2 reports whether v is an [iter.Seq2] where the second value is an error.
+func IsSeq2(v any) bool {
+	t := reflect.TypeOf(v)
+	return t != nil && t.Kind() == reflect.Func && t.CanSeq2() && t.In(0).In(1) == reflect.TypeFor[error]()
+}
+
+// Collect consumes a sequence supported by [IsSeq] or [IsSeq2] and returns the items in a slice, which can be passed
+// to renderers that does not support streaming, like [Table] and [JSON]. The first non-nil error yielded by an
+// [iter.Seq2] is returned. Values that are not sequences are returned as is.
+func Collect(v any) (any, error) {
+	if !IsSeq(v) && !IsSeq2(v) {
+		return v, nil
+	}
+
+	rv := reflect.ValueOf(v)
+	items := reflect.MakeSlice(reflect.SliceOf(rv.Type().In(0).In(0)), 0, 0)
+	if IsSeq(v) {
+		for item := range rv.Seq() {
+			items = reflect.Append(items, item)
+		}
+		return items.Interface(), nil
+	}
+
+	for item, err := range rv.Seq2() {
+		if !err.IsNil() {
+			return nil, err.Interface().(error)
+		}
+		items = reflect.Append(items, item)
+	}
+
+	return items.Interface(), nil
+}
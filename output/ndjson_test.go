@@ -0,0 +1,105 @@
+package output_test
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"iter"
+	"slices"
+	"testing"
+
+	"github.com/nais/naistrix/output"
+)
+
+func TestNDJSON(t *testing.T) {
+	type item struct {
+		Name string `json:"name"`
+	}
+
+	items := []item{{Name: "a"}, {Name: "b"}}
+
+	tests := []struct {
+		name          string
+		data          any
+		expected      string
+		expectedError string
+	}{
+		{
+			name:     "single value",
+			data:     item{Name: "a"},
+			expected: "{\"name\":\"a\"}\n",
+		},
+		{
+			name:     "slice",
+			data:     items,
+			expected: "{\"name\":\"a\"}\n{\"name\":\"b\"}\n",
+		},
+		{
+			name:     "sequence",
+			data:     slices.Values(items),
+			expected: "{\"name\":\"a\"}\n{\"name\":\"b\"}\n",
+		},
+		{
+			name: "sequence with error",
+			data: iter.Seq2[item, error](func(yield func(item, error) bool) {
+				if !yield(item{Name: "a"}, nil) {
+					return
+				}
+				yield(item{}, errors.New("page failed"))
+			}),
+			expected:      "{\"name\":\"a\"}\n",
+			expectedError: "page failed",
+		},
+		{
+			name:          "unsupported sequence",
+			data:          slices.All(items),
+			expectedError: "unsupported sequence type",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := output.NewNDJSON(&buf).Render(tt.data)
+			if tt.expectedError != "" {
+				if err == nil || !bytes.Contains([]byte(err.Error()), []byte(tt.expectedError)) {
+					t.Fatalf("expected error containing %q, got: %v", tt.expectedError, err)
+				}
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if actual := buf.String(); actual != tt.expected {
+				t.Fatalf("expected %q, got: %q", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestNDJSON_WriteFlushes(t *testing.T) {
+	var buf bytes.Buffer
+	w := bufio.NewWriter(&buf)
+
+	if err := output.NewNDJSON(w).Write("line"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if expected, actual := "\"line\"\n", buf.String(); actual != expected {
+		t.Fatalf("expected %q to be flushed, got: %q", expected, actual)
+	}
+}
+
+func TestCollect(t *testing.T) {
+	collected, err := output.Collect(slices.Values([]string{"a", "b"}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if s, ok := collected.([]string); !ok || !slices.Equal(s, []string{"a", "b"}) {
+		t.Fatalf("expected []string{\"a\", \"b\"}, got: %#v", collected)
+	}
+
+	if v, _ := output.Collect("value"); v != "value" {
+		t.Fatalf("expected non-sequence value to be returned as is, got: %#v", v)
+	}
+}
@@ -1,3 +1,16 @@
 // Package output defines a set of "rich" output renderers for more complex output formats, like for instance tables and
 // JSON-encoded data.
 package output
+
+// Renderer is implemented by all renderers in this package, and can be implemented by custom renderers as well.
+type Renderer interface {
+	// Render renders v to the destination of the renderer.
+	Render(v any) error
+}
+
+// Validator can be implemented by renderers to validate their configuration, like a query expression, before any data
+// is rendered. This makes it possible to report invalid configurations to the end-user before doing any work.
+type Validator interface {
+	// Validate returns an error if the configuration of the renderer is invalid.
+	Validate() error
+}
@@ -0,0 +1,768 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// QueryError is returned when a query expression can not be parsed.
+type QueryError struct {
+	// Expression is the invalid query expression.
+	Expression string
+
+	// Position is the byte offset in the expression where the error was found.
+	Position int
+
+	// Reason describes what is wrong with the expression.
+	Reason string
+}
+
+// Error returns a description of the invalid query expression.
+func (e *QueryError) Error() string {
+	return fmt.Sprintf("invalid query %q: %s at position %d", e.Expression, e.Reason, e.Position)
+}
+
+// Query is a parsed query expression that can be used to select parts of a value. Use [ParseQuery] to construct one.
+//
+// The expression syntax is a subset of JSONPath, as known from kubectl, which also accepts the most common jq path
+// expressions:
+//
+//	.              the value itself
+//	.name          the field "name" of an object, also written as ["name"] or ['name']
+//	[0], [-1]      an element of an array, negative indices count from the end
+//	[*], [], .*    all elements of an array, or all field values of an object
+//	[1:3]          a slice of an array, with an optional step: [start:end:step]
+//	[0,2]          a union of indices, or of quoted field names
+//	..name         the field "name" at any depth
+//	[?(@.x > 1)]   elements where the filter matches, supports ==, !=, <, <=, >, >= and existence checks
+//	a | b          evaluates b for each result of a
+//
+// The expression can optionally start with $ and be wrapped in braces, like for instance {$.items[*].name}.
+type Query struct {
+	expression string
+	stages     [][]queryStep
+}
+
+// queryStepKind is the kind of a single step in a query path.
+type queryStepKind int
+
+const (
+	queryStepFields queryStepKind = iota
+	queryStepIndices
+	queryStepWildcard
+	queryStepSlice
+	queryStepDescendants
+	queryStepFilter
+)
+
+// queryStep is a single step in a query path.
+type queryStep struct {
+	kind    queryStepKind
+	fields  []string
+	indices []int
+	slice   [3]*int
+	filter  *queryFilter
+}
+
+// queryFilter is a filter expression, like @.name == "value".
+type queryFilter struct {
+	left     queryOperand
+	operator string
+	right    queryOperand
+}
+
+// queryOperand is one side of a filter expression, either a path relative to the current (@) or root ($) value, or a
+// literal.
+type queryOperand struct {
+	path    []queryStep
+	root    bool
+	literal any
+	isPath  bool
+}
+
+// ParseQuery parses a query expression. A [*QueryError] is returned when the expression is invalid. See [Query] for
+// the supported syntax.
+func ParseQuery(expression string) (*Query, error) {
+	expr := strings.TrimSpace(expression)
+	if strings.HasPrefix(expr, "{") && strings.HasSuffix(expr, "}") {
+		expr = expr[1 : len(expr)-1]
+	}
+
+	p := &queryParser{expression: expression, input: expr, offset: strings.Index(expression, expr)}
+	q := &Query{expression: expression}
+	for {
+		p.skipSpaces()
+		if p.peek() == '$' {
+			p.pos++
+		}
+
+		steps, err := p.parsePath()
+		if err != nil {
+			return nil, err
+		}
+		q.stages = append(q.stages, steps)
+
+		p.skipSpaces()
+		if p.eof() {
+			return q, nil
+		}
+
+		if p.peek() != '|' {
+			return nil, p.errorf("unexpected character %q", p.peek())
+		}
+		p.pos++
+	}
+}
+
+// Definite reports whether the query selects at most one value, meaning that it does not contain any wildcards,
+// slices, unions, filters or recursive descents.
+func (q *Query) Definite() bool {
+	for _, stage := range q.stages {
+		for _, step := range stage {
+			switch step.kind {
+			case queryStepFields:
+				if len(step.fields) > 1 {
+					return false
+				}
+			case queryStepIndices:
+				if len(step.indices) > 1 {
+					return false
+				}
+			default:
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Execute runs the query against v, and returns all selected values. The value is encoded as JSON before the query is
+// executed, so the field names used in the query are the JSON field names.
+func (q *Query) Execute(v any) ([]any, error) {
+	doc, err := normalizeJSON(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return q.execute(doc), nil
+}
+
+// execute runs the query against a normalized value, consisting only of maps, slices and scalar values.
+func (q *Query) execute(doc any) []any {
+	nodes := []any{doc}
+	for _, stage := range q.stages {
+		nodes = evaluatePath(stage, nodes, doc)
+	}
+	return nodes
+}
+
+// result runs the query against a normalized value. For definite queries the single result is returned, or nil when
+// there is no result. Otherwise, a slice of all results is returned.
+func (q *Query) result(doc any) any {
+	results := q.execute(doc)
+	if !q.Definite() {
+		if results == nil {
+			return []any{}
+		}
+		return results
+	}
+
+	if len(results) == 0 {
+		return nil
+	}
+	return results[0]
+}
+
+// normalizeJSON converts v to a value consisting only of maps, slices and scalar values, by encoding it as JSON and
+// decoding it again. Numbers are kept as json.Number to avoid loss of precision.
+func normalizeJSON(v any) (any, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+
+	var doc any
+	if err := dec.Decode(&doc); err != nil {
+		return nil, err
+	}
+
+	return doc, nil
+}
+
+// evaluatePath applies the steps of a path to all nodes, and returns the resulting nodes.
+func evaluatePath(steps []queryStep, nodes []any, root any) []any {
+	for _, step := range steps {
+		var next []any
+		for _, node := range nodes {
+			next = append(next, step.apply(node, root)...)
+		}
+		nodes = next
+	}
+	return nodes
+}
+
+// apply applies the step to a single node.
+func (s queryStep) apply(node, root any) []any {
+	var ret []any
+	switch s.kind {
+	case queryStepFields:
+		if m, ok := node.(map[string]any); ok {
+			for _, f := range s.fields {
+				if v, ok := m[f]; ok {
+					ret = append(ret, v)
+				}
+			}
+		}
+	case queryStepIndices:
+		if l, ok := node.([]any); ok {
+			for _, i := range s.indices {
+				if i < 0 {
+					i += len(l)
+				}
+				if i >= 0 && i < len(l) {
+					ret = append(ret, l[i])
+				}
+			}
+		}
+	case queryStepWildcard:
+		ret = children(node)
+	case queryStepSlice:
+		if l, ok := node.([]any); ok {
+			start, end, step := sliceBounds(s.slice, len(l))
+			for i := start; i < end; i += step {
+				ret = append(ret, l[i])
+			}
+		}
+	case queryStepDescendants:
+		ret = descendants(node)
+	case queryStepFilter:
+		for _, child := range children(node) {
+			if s.filter.matches(child, root) {
+				ret = append(ret, child)
+			}
+		}
+	}
+	return ret
+}
+
+// children returns the elements of a slice, or the values of a map sorted by key.
+func children(node any) []any {
+	switch n := node.(type) {
+	case []any:
+		return n
+	case map[string]any:
+		ret := make([]any, 0, len(n))
+		for _, k := range slices.Sorted(maps.Keys(n)) {
+			ret = append(ret, n[k])
+		}
+		return ret
+	default:
+		return nil
+	}
+}
+
+// descendants returns the node itself along with all nested values.
+func descendants(node any) []any {
+	ret := []any{node}
+	for _, child := range children(node) {
+		ret = append(ret, descendants(child)...)
+	}
+	return ret
+}
+
+// sliceBounds resolves the start, end and step of a slice step for a slice with the provided length.
+func sliceBounds(spec [3]*int, length int) (int, int, int) {
+	resolve := func(i *int, def int) int {
+		if i == nil {
+			return def
+		}
+
+		v := *i
+		if v < 0 {
+			v += length
+		}
+		return min(max(v, 0), length)
+	}
+
+	step := 1
+	if spec[2] != nil {
+		step = *spec[2]
+	}
+
+	return resolve(spec[0], 0), resolve(spec[1], length), step
+}
+
+// matches reports whether the filter matches the provided node.
+func (f *queryFilter) matches(node, root any) bool {
+	left := f.left.values(node, root)
+	if f.operator == "" {
+		return len(left) > 0 && truthy(left[0])
+	}
+
+	right := f.right.values(node, root)
+	if len(left) == 0 || len(right) == 0 {
+		return false
+	}
+
+	return compare(left[0], right[0], f.operator)
+}
+
+// values returns the values of the operand.
+func (o queryOperand) values(node, root any) []any {
+	if !o.isPath {
+		return []any{o.literal}
+	}
+
+	if o.root {
+		node = root
+	}
+
+	return evaluatePath(o.path, []any{node}, root)
+}
+
+// truthy reports whether a value used in an existence check should be considered present.
+func truthy(v any) bool {
+	switch v := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	default:
+		return true
+	}
+}
+
+// compare compares two values using the provided operator. Numbers and strings can be compared using all operators,
+// while other values only can be compared for equality.
+func compare(a, b any, operator string) bool {
+	if x, ok := toFloat(a); ok {
+		if y, ok := toFloat(b); ok {
+			switch operator {
+			case "==":
+				return x == y
+			case "!=":
+				return x != y
+			case "<":
+				return x < y
+			case "<=":
+				return x <= y
+			case ">":
+				return x > y
+			case ">=":
+				return x >= y
+			}
+		}
+	}
+
+	if x, ok := a.(string); ok {
+		if y, ok := b.(string); ok {
+			switch operator {
+			case "==":
+				return x == y
+			case "!=":
+				return x != y
+			case "<":
+				return x < y
+			case "<=":
+				return x <= y
+			case ">":
+				return x > y
+			case ">=":
+				return x >= y
+			}
+		}
+	}
+
+	switch operator {
+	case "==":
+		return reflect.DeepEqual(a, b)
+	case "!=":
+		return !reflect.DeepEqual(a, b)
+	default:
+		return false
+	}
+}
+
+// toFloat converts numeric values to float64.
+func toFloat(v any) (float64, bool) {
+	switch n := v.(type) {
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	default:
+		return 0, false
+	}
+}
+
+// queryParser is a parser for query expressions.
+type queryParser struct {
+	// expression is the expression as passed by the user, used in errors.
+	expression string
+
+	// input is the part of the expression being parsed.
+	input string
+
+	// offset is the offset of input in expression, used to report correct positions in errors.
+	offset int
+
+	// pos is the current position in input.
+	pos int
+}
+
+func (p *queryParser) eof() bool {
+	return p.pos >= len(p.input)
+}
+
+func (p *queryParser) peek() byte {
+	if p.eof() {
+		return 0
+	}
+	return p.input[p.pos]
+}
+
+func (p *queryParser) skipSpaces() {
+	for !p.eof() && (p.peek() == ' ' || p.peek() == '\t') {
+		p.pos++
+	}
+}
+
+func (p *queryParser) errorf(format string, a ...any) *QueryError {
+	return &QueryError{
+		Expression: p.expression,
+		Position:   p.offset + p.pos,
+		Reason:     fmt.Sprintf(format, a...),
+	}
+}
+
+// parsePath parses steps until a character that can not be part of a path is found.
+func (p *queryParser) parsePath() ([]queryStep, error) {
+	steps := make([]queryStep, 0)
+	for !p.eof() {
+		switch {
+		case strings.HasPrefix(p.input[p.pos:], ".."):
+			p.pos += 2
+			steps = append(steps, queryStep{kind: queryStepDescendants})
+
+			switch c := p.peek(); {
+			case c == '*':
+				p.pos++
+				steps = append(steps, queryStep{kind: queryStepWildcard})
+			case c == '[':
+			case isNameChar(c):
+				steps = append(steps, queryStep{kind: queryStepFields, fields: []string{p.parseName()}})
+			default:
+				return nil, p.errorf("expected field name after ..")
+			}
+		case p.peek() == '.':
+			p.pos++
+
+			switch c := p.peek(); {
+			case c == '*':
+				p.pos++
+				steps = append(steps, queryStep{kind: queryStepWildcard})
+			case isNameChar(c):
+				steps = append(steps, queryStep{kind: queryStepFields, fields: []string{p.parseName()}})
+			}
+		case p.peek() == '[':
+			step, err := p.parseBracket()
+			if err != nil {
+				return nil, err
+			}
+			steps = append(steps, step)
+		default:
+			return steps, nil
+		}
+	}
+
+	return steps, nil
+}
+
+// parseName parses a field name.
+func (p *queryParser) parseName() string {
+	start := p.pos
+	for !p.eof() && isNameChar(p.peek()) {
+		p.pos++
+	}
+	return p.input[start:p.pos]
+}
+
+// isNameChar reports whether c can be part of a field name.
+func isNameChar(c byte) bool {
+	return c != 0 && !strings.ContainsRune(".[]()|,=!<>@$?*:'\" \t{}", rune(c))
+}
+
+// parseBracket parses a bracket step, like [0], [*], ['name'], [1:2] or [?(@.a == 1)].
+func (p *queryParser) parseBracket() (queryStep, error) {
+	p.pos++ // [
+	p.skipSpaces()
+
+	var step queryStep
+	switch c := p.peek(); {
+	case c == ']':
+		step = queryStep{kind: queryStepWildcard}
+	case c == '*':
+		p.pos++
+		step = queryStep{kind: queryStepWildcard}
+	case c == '?':
+		p.pos++
+		if p.peek() != '(' {
+			return step, p.errorf("expected ( after ?")
+		}
+		p.pos++
+
+		filter, err := p.parseFilter()
+		if err != nil {
+			return step, err
+		}
+
+		p.skipSpaces()
+		if p.peek() != ')' {
+			return step, p.errorf("expected ) to close filter")
+		}
+		p.pos++
+		step = queryStep{kind: queryStepFilter, filter: filter}
+	case c == '\'' || c == '"':
+		step = queryStep{kind: queryStepFields}
+		for {
+			s, err := p.parseString()
+			if err != nil {
+				return step, err
+			}
+			step.fields = append(step.fields, s)
+
+			p.skipSpaces()
+			if p.peek() != ',' {
+				break
+			}
+			p.pos++
+			p.skipSpaces()
+		}
+	case c == '-' || c == ':' || (c >= '0' && c <= '9'):
+		var err error
+		if step, err = p.parseIndices(); err != nil {
+			return step, err
+		}
+	default:
+		return step, p.errorf("unexpected character %q in brackets", c)
+	}
+
+	p.skipSpaces()
+	if p.peek() != ']' {
+		return step, p.errorf("expected ]")
+	}
+	p.pos++
+
+	return step, nil
+}
+
+// parseIndices parses indices, like 1 or 1,2, or a slice, like 1:2 or ::2.
+func (p *queryParser) parseIndices() (queryStep, error) {
+	first, err := p.parseOptionalInt()
+	if err != nil {
+		return queryStep{}, err
+	}
+
+	p.skipSpaces()
+	if p.peek() == ':' {
+		spec := [3]*int{first}
+		for i := 1; i < 3 && p.peek() == ':'; i++ {
+			p.pos++
+			p.skipSpaces()
+			if spec[i], err = p.parseOptionalInt(); err != nil {
+				return queryStep{}, err
+			}
+			p.skipSpaces()
+		}
+
+		if spec[2] != nil && *spec[2] <= 0 {
+			return queryStep{}, p.errorf("slice step must be a positive number")
+		}
+
+		return queryStep{kind: queryStepSlice, slice: spec}, nil
+	}
+
+	if first == nil {
+		return queryStep{}, p.errorf("expected index")
+	}
+
+	step := queryStep{kind: queryStepIndices, indices: []int{*first}}
+	for p.peek() == ',' {
+		p.pos++
+		p.skipSpaces()
+
+		i, err := p.parseOptionalInt()
+		if err != nil {
+			return queryStep{}, err
+		} else if i == nil {
+			return queryStep{}, p.errorf("expected index")
+		}
+
+		step.indices = append(step.indices, *i)
+		p.skipSpaces()
+	}
+
+	return step, nil
+}
+
+// parseOptionalInt parses an integer, and returns nil if there is no integer at the current position.
+func (p *queryParser) parseOptionalInt() (*int, error) {
+	start := p.pos
+	if p.peek() == '-' {
+		p.pos++
+	}
+
+	for !p.eof() && p.peek() >= '0' && p.peek() <= '9' {
+		p.pos++
+	}
+
+	if p.pos == start {
+		return nil, nil
+	}
+
+	s := p.input[start:p.pos]
+	i, err := strconv.Atoi(s)
+	if err != nil {
+		p.pos = start
+		return nil, p.errorf("invalid number %q", s)
+	}
+
+	return &i, nil
+}
+
+// parseString parses a single or double quoted string.
+func (p *queryParser) parseString() (string, error) {
+	quote := p.peek()
+	start := p.pos
+	p.pos++
+
+	var sb strings.Builder
+	for {
+		if p.eof() {
+			p.pos = start
+			return "", p.errorf("unterminated string")
+		}
+
+		c := p.peek()
+		p.pos++
+
+		switch c {
+		case quote:
+			return sb.String(), nil
+		case '\\':
+			if p.eof() {
+				continue
+			}
+
+			e := p.peek()
+			p.pos++
+			switch e {
+			case 'n':
+				sb.WriteByte('\n')
+			case 't':
+				sb.WriteByte('\t')
+			default:
+				sb.WriteByte(e)
+			}
+		default:
+			sb.WriteByte(c)
+		}
+	}
+}
+
+// parseFilter parses a filter expression, like @.name == 'value' or @.name.
+func (p *queryParser) parseFilter() (*queryFilter, error) {
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	p.skipSpaces()
+	if p.peek() == ')' {
+		if !left.isPath {
+			return nil, p.errorf("expected comparison operator")
+		}
+		return &queryFilter{left: left}, nil
+	}
+
+	var operator string
+	for _, op := range []string{"==", "!=", "<=", ">=", "<", ">"} {
+		if strings.HasPrefix(p.input[p.pos:], op) {
+			operator = op
+			break
+		}
+	}
+
+	if operator == "" {
+		return nil, p.errorf("expected comparison operator")
+	}
+	p.pos += len(operator)
+
+	right, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	return &queryFilter{left: left, operator: operator, right: right}, nil
+}
+
+// parseOperand parses a filter operand, either a path starting with @ or $, or a literal.
+func (p *queryParser) parseOperand() (queryOperand, error) {
+	p.skipSpaces()
+
+	switch c := p.peek(); {
+	case c == '@' || c == '$':
+		p.pos++
+		steps, err := p.parsePath()
+		if err != nil {
+			return queryOperand{}, err
+		}
+		return queryOperand{path: steps, root: c == '$', isPath: true}, nil
+	case c == '\'' || c == '"':
+		s, err := p.parseString()
+		if err != nil {
+			return queryOperand{}, err
+		}
+		return queryOperand{literal: s}, nil
+	case c == '-' || (c >= '0' && c <= '9'):
+		start := p.pos
+		p.pos++
+		for !p.eof() && strings.ContainsRune("0123456789.eE+-", rune(p.peek())) {
+			p.pos++
+		}
+
+		f, err := strconv.ParseFloat(p.input[start:p.pos], 64)
+		if err != nil {
+			p.pos = start
+			return queryOperand{}, p.errorf("invalid number")
+		}
+		return queryOperand{literal: f}, nil
+	}
+
+	for word, literal := range map[string]any{"true": true, "false": false, "null": nil} {
+		if strings.HasPrefix(p.input[p.pos:], word) {
+			p.pos += len(word)
+			return queryOperand{literal: literal}, nil
+		}
+	}
+
+	return queryOperand{}, p.errorf("expected @, $ or a literal value")
+}
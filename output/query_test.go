@@ -0,0 +1,180 @@
+package output_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/nais/naistrix/output"
+)
+
+func TestQuery(t *testing.T) {
+	type container struct {
+		Name  string `json:"name"`
+		Image string `json:"image"`
+	}
+
+	type app struct {
+		Name       string            `json:"name"`
+		Replicas   int               `json:"replicas"`
+		Labels     map[string]string `json:"labels"`
+		Containers []container       `json:"containers"`
+	}
+
+	data := map[string]any{
+		"items": []app{
+			{
+				Name:     "a",
+				Replicas: 1,
+				Labels:   map[string]string{"team": "x"},
+				Containers: []container{
+					{Name: "main", Image: "a:1"},
+					{Name: "sidecar", Image: "proxy:1"},
+				},
+			},
+			{
+				Name:       "b",
+				Replicas:   3,
+				Labels:     map[string]string{},
+				Containers: []container{{Name: "main", Image: "b:2"}},
+			},
+		},
+	}
+
+	tests := []struct {
+		expression string
+		expected   string
+		definite   bool
+	}{
+		{expression: ".", expected: `[{"items":[{"containers":[{"image":"a:1","name":"main"},{"image":"proxy:1","name":"sidecar"}],"labels":{"team":"x"},"name":"a","replicas":1},{"containers":[{"image":"b:2","name":"main"}],"labels":{},"name":"b","replicas":3}]}]`, definite: true},
+		{expression: ".items[0].name", expected: `["a"]`, definite: true},
+		{expression: "{$.items[-1].name}", expected: `["b"]`, definite: true},
+		{expression: ".items[5].name", expected: `null`, definite: true},
+		{expression: `$['items'][0]["labels"].team`, expected: `["x"]`, definite: true},
+		{expression: ".items[*].name", expected: `["a","b"]`},
+		{expression: ".items[].name", expected: `["a","b"]`},
+		{expression: ".items.*.replicas", expected: `[1,3]`},
+		{expression: ".items[0:1].name", expected: `["a"]`},
+		{expression: ".items[::2].name", expected: `["a"]`},
+		{expression: ".items[0,1].replicas", expected: `[1,3]`},
+		{expression: "..image", expected: `["a:1","proxy:1","b:2"]`},
+		{expression: ".items[?(@.replicas > 1)].name", expected: `["b"]`},
+		{expression: ".items[?(@.labels.team)].name", expected: `["a"]`},
+		{expression: `.items[*].containers[?(@.name != "main")].image`, expected: `["proxy:1"]`},
+		{expression: ".items[] | .containers[0] | .image", expected: `["a:1","b:2"]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			q, err := output.ParseQuery(tt.expression)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if q.Definite() != tt.definite {
+				t.Fatalf("expected definite to be %v", tt.definite)
+			}
+
+			results, err := q.Execute(data)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			b, err := json.Marshal(results)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if actual := string(b); actual != tt.expected {
+				t.Fatalf("expected %s, got: %s", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestQuery_InvalidExpression(t *testing.T) {
+	tests := []struct {
+		expression string
+		position   int
+		reason     string
+	}{
+		{expression: ".items[0", position: 8, reason: "expected ]"},
+		{expression: "items", position: 0, reason: `unexpected character 'i'`},
+		{expression: ".items[?(@.a ~ 1)]", position: 13, reason: "expected comparison operator"},
+		{expression: "{.items['a}", position: 8, reason: "unterminated string"},
+		{expression: ".items[::0]", position: 10, reason: "slice step must be a positive number"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			_, err := output.ParseQuery(tt.expression)
+
+			qe, ok := errors.AsType[*output.QueryError](err)
+			if !ok {
+				t.Fatalf("expected *output.QueryError, got: %v", err)
+			}
+
+			if qe.Position != tt.position || qe.Reason != tt.reason {
+				t.Fatalf("expected %q at position %d, got: %q at position %d", tt.reason, tt.position, qe.Reason, qe.Position)
+			}
+		})
+	}
+}
+
+func TestQuery_Renderers(t *testing.T) {
+	type user struct {
+		Name  string `json:"name" yaml:"userName"`
+		Email string `json:"email" yaml:"email"`
+	}
+
+	users := []user{{Name: "jane", Email: "jane@example.com"}, {Name: "john", Email: "john@example.com"}}
+
+	tests := []struct {
+		name     string
+		renderer func(*bytes.Buffer) output.Renderer
+		expected string
+	}{
+		{
+			name: "json",
+			renderer: func(buf *bytes.Buffer) output.Renderer {
+				return output.NewJSON(buf, output.JSONWithQuery(".[*].name"))
+			},
+			expected: "[\"jane\",\"john\"]\n",
+		},
+		{
+			name: "yaml uses yaml field names",
+			renderer: func(buf *bytes.Buffer) output.Renderer {
+				return output.NewYAML(buf, output.YAMLWithQuery(".[0].userName"))
+			},
+			expected: "jane\n",
+		},
+		{
+			name: "jsonpath template",
+			renderer: func(buf *bytes.Buffer) output.Renderer {
+				return output.NewJSONPath(buf, `{.[0].name}{"\t"}{.[*].email}`)
+			},
+			expected: "jane\tjane@example.com john@example.com\n",
+		},
+		{
+			name: "jsonpath without braces",
+			renderer: func(buf *bytes.Buffer) output.Renderer {
+				return output.NewJSONPath(buf, `.[1]`)
+			},
+			expected: "{\"email\":\"john@example.com\",\"name\":\"john\"}\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := tt.renderer(&buf).Render(users); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if actual := buf.String(); actual != tt.expected {
+				t.Fatalf("expected %q, got: %q", tt.expected, actual)
+			}
+		})
+	}
+}
@@ -2,10 +2,16 @@ package output
 
 import (
 	"bytes"
+	"cmp"
 	"fmt"
 	"io"
 	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/mattn/go-runewidth"
 	"github.com/nais/naistrix/internal/color"
 	"github.com/pterm/pterm"
 )
@@ -17,7 +23,60 @@ type TableOptionFunc func(*Table)
 // `hidden:"true"` tag.
 func TableWithShowHiddenColumns() TableOptionFunc {
 	return func(t *Table) {
-		t.showHidden = true
+		t.selection.showHidden = true
+	}
+}
+
+// TableWithWideColumns can be used to render the fields in a struct that have the `wide:"true"` tag. These columns are
+// left out by default, and are typically shown when the end-user asks for wide output.
+func TableWithWideColumns() TableOptionFunc {
+	return func(t *Table) {
+		t.selection.wide = true
+	}
+}
+
+// TableWithMaxWidth can be used to limit the width of the table, typically to the width of the terminal. When the table
+// is wider than the provided width, the widest columns are shrunk, and cells that do not fit are truncated with an
+// ellipsis. Columns are never shrunk to less than a few characters, so very narrow widths can still be exceeded. A
+// width of zero or less disables the limit, which is the default.
+func TableWithMaxWidth(width int) TableOptionFunc {
+	return func(t *Table) {
+		t.maxWidth = width
+	}
+}
+
+// TableWithColumns can be used to only render the columns with the provided headings, in the provided order. Headings
+// are matched without regard to case, and columns with the `hidden:"true"` or `wide:"true"` tags can be selected as
+// well.
+func TableWithColumns(headings ...string) TableOptionFunc {
+	return func(t *Table) {
+		t.selection.columns = headings
+	}
+}
+
+// TableWithSortBy can be used to sort the rows by one or more columns, identified by their headings. Prefix a heading
+// with "-" to sort by the column in descending order. Values of numeric, boolean and [time.Time] struct fields are
+// compared by value, while other values are compared by their string representation.
+func TableWithSortBy(headings ...string) TableOptionFunc {
+	return func(t *Table) {
+		t.selection.sortBy = headings
+	}
+}
+
+// TableWithFilters can be used to only render rows matching all the provided filters. A filter is written as
+// "heading=value", or "heading!=value" to exclude matching rows, and is compared to the rendered value of the column.
+func TableWithFilters(filters ...string) TableOptionFunc {
+	return func(t *Table) {
+		t.selection.filters = filters
+	}
+}
+
+// TableWithSampleSize sets the number of rows a [TableStream] buffers before writing the header, to decide the widths
+// of the columns. A larger sample gives better column widths, at the expense of a longer wait before the first rows are
+// written. The default is 20 rows.
+func TableWithSampleSize(n int) TableOptionFunc {
+	return func(t *Table) {
+		t.sampleSize = n
 	}
 }
 
@@ -43,9 +102,14 @@ func TableWithMargins() TableOptionFunc {
 	}
 }
 
+// defaultSampleSize is the default number of rows a [TableStream] buffers before writing the header.
+const defaultSampleSize = 20
+
 // Table is a renderer that writes tabular data to an [io.Writer]. Use [NewTable] to construct one.
 type Table struct {
-	showHidden   bool
+	selection    rowSelection
+	maxWidth     int
+	sampleSize   int
 	tablePrinter pterm.TablePrinter
 	writer       io.Writer
 	topMargin    bool
@@ -56,6 +120,8 @@ type Table struct {
 // available [TableOptionFunc] functions.
 func NewTable(w io.Writer, opts ...TableOptionFunc) *Table {
 	t := &Table{
+		selection:    rowSelection{formatted: true},
+		sampleSize:   defaultSampleSize,
 		tablePrinter: pterm.DefaultTable,
 		writer:       w,
 	}
@@ -73,29 +139,42 @@ func NewTable(w io.Writer, opts ...TableOptionFunc) *Table {
 // If a slice of structs is used, all exported fields in the provided struct will be added as columns. The field names
 // will be used as headers, and can be overridden using a `heading` field tag. Fields can be hidden using a `hidden`
 // field tag set to "true". To show hidden fields, use the TableWithShowHiddenColumns option when creating the table.
+// Fields with a `wide` field tag set to "true" are only shown when using the TableWithWideColumns option. The width of
+// a column can be fixed using a `width` field tag, like `width:"20"`, in which case longer cells are truncated with an
+// ellipsis. See [TableCell] for the tags and interfaces that control how values are formatted.
 //
 // If a slice of string slices is used, the first string slice will be used for headings, and the remaining slices as
 // rows. It is not possible to have hidden columns when using this method.
+//
+// The data can also be an [iter.Seq] of structs, or an [iter.Seq2] of structs where the second value is an error. The
+// rows are then written as they are yielded, as described in [Table.Stream], and the first non-nil error stops the
+// rendering and is returned.
+//
+// The columns, sorting and filtering of the rows can be controlled using the TableWithColumns, TableWithSortBy and
+// TableWithFilters options. When all rows are removed by the filters, only the headers are rendered.
 func (t *Table) Render(data any) error {
-	tableData, err := t.convert(data)
+	if IsSeq(data) || IsSeq2(data) {
+		if typ := seqStructType(data); typ != nil {
+			return t.renderSeq(data, typ)
+		}
+
+		var err error
+		if data, err = Collect(data); err != nil {
+			return err
+		}
+	}
+
+	columns, rows, err := extractRows(data, t.selection)
 	if err != nil {
 		return err
 	}
 
-	var buf bytes.Buffer
-	err = t.tablePrinter.
-		WithWriter(&buf).
-		WithHasHeader(true).
-		WithHeaderRowSeparator("-").
-		WithData(tableData).
-		Render()
+	widths := t.columnWidths(columns, rows)
+	b, err := t.render(rows, columns, widths, widths, true)
 	if err != nil {
 		return err
 	}
 
-	// fix double newlines added by pterm
-	b := bytes.TrimRight(buf.Bytes(), "\n")
-
 	if t.topMargin {
 		b = append([]byte{'\n'}, b...)
 	}
@@ -111,76 +190,370 @@ func (t *Table) Render(data any) error {
 	return nil
 }
 
-// convert converts the provided data into pterm.TableData.
-func (t *Table) convert(v any) (pterm.TableData, error) {
-	vt := reflect.TypeOf(v)
-	d := reflect.ValueOf(v)
-	if vt.Kind() != reflect.Slice || d.Len() == 0 {
-		return nil, fmt.Errorf("data must be a non-empty slice, got %T", v)
+// renderSeq streams the structs yielded by the sequence in data, which holds values of the provided struct type.
+func (t *Table) renderSeq(data any, typ reflect.Type) error {
+	s := t.Stream()
+	if err := s.init(typ); err != nil {
+		return err
 	}
 
-	if elem := vt.Elem(); elem.Kind() == reflect.Slice && elem.Elem().Kind() == reflect.String {
-		if d, ok := v.([][]string); ok {
-			ret := make(pterm.TableData, len(d))
-			ret[0] = d[0]
-			for i := 1; i < len(d); i++ {
-				ret[i] = color.ColorizeStrings(d[i])
+	rv := reflect.ValueOf(data)
+	if IsSeq(data) {
+		for item := range rv.Seq() {
+			if err := s.Write(item.Interface()); err != nil {
+				return err
 			}
-			return ret, nil
 		}
+		return s.Close()
+	}
+
+	for item, err := range rv.Seq2() {
+		if !err.IsNil() {
+			return err.Interface().(error)
+		}
+
+		if err := s.Write(item.Interface()); err != nil {
+			return err
+		}
+	}
+	return s.Close()
+}
 
-		return nil, fmt.Errorf("unable to convert data")
+// seqStructType returns the struct type of the values yielded by the sequence in v, or nil if the values are not
+// structs or pointers to structs.
+func seqStructType(v any) reflect.Type {
+	typ := reflect.TypeOf(v).In(0).In(0)
+	if typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
 	}
 
-	// extract headers from the first struct in the slice
-	headers, err := t.extractHeaders(d.Index(0))
+	if typ.Kind() != reflect.Struct {
+		return nil
+	}
+	return typ
+}
+
+// render renders rows with the provided columns as a table, without a trailing newline. The cells are truncated to the
+// limits and padded to the widths of their columns, and the first row is rendered as headers when header is set. Limits
+// of zero or less leave the cells of a column as is.
+func (t *Table) render(rows [][]string, columns []tableColumn, widths, limits []int, header bool) ([]byte, error) {
+	truncateCells(rows, limits)
+	padCells(rows, columns, widths)
+
+	var buf bytes.Buffer
+	err := t.tablePrinter.
+		WithWriter(&buf).
+		WithHasHeader(header).
+		WithHeaderRowSeparator("-").
+		WithData(rows).
+		Render()
 	if err != nil {
 		return nil, err
 	}
 
-	if len(headers) == 0 {
-		return nil, fmt.Errorf("no visible fields in struct")
+	// fix double newlines added by pterm
+	return bytes.TrimRight(buf.Bytes(), "\n"), nil
+}
+
+// columnWidths returns the widths of the provided columns, which are either declared using the `width` field tag, or
+// the width of the widest cell in rows. When the table has a maximum width, the widest columns are shrunk until the
+// table fits.
+func (t *Table) columnWidths(columns []tableColumn, rows [][]string) []int {
+	widths := make([]int, len(columns))
+	for i, col := range columns {
+		if col.width > 0 {
+			widths[i] = col.width
+			continue
+		}
+
+		for _, row := range rows {
+			widths[i] = max(widths[i], cellWidth(row[i]))
+		}
 	}
 
-	td := pterm.TableData{headers}
-	for i := 0; i < d.Len(); i++ {
-		row := d.Index(i)
+	if t.maxWidth > 0 {
+		shrinkWidths(widths, t.maxWidth, runewidth.StringWidth(t.tablePrinter.Separator))
+	}
 
-		if row.Kind() == reflect.Pointer {
-			if row.IsNil() {
-				return nil, fmt.Errorf("nil pointer in slice at index %d", i)
+	return widths
+}
+
+// minColumnWidth is the minimum width a column is shrunk to when fitting a table to a maximum width.
+const minColumnWidth = 4
+
+// shrinkWidths shrinks the column widths, so that the rendered table does not exceed maxWidth. The widest columns are
+// shrunk first, and columns are never shrunk to less than minColumnWidth.
+func shrinkWidths(widths []int, maxWidth, separatorWidth int) {
+	total := separatorWidth * (len(widths) - 1)
+	for _, w := range widths {
+		total += w
+	}
+
+	for total > maxWidth {
+		widest := 0
+		for i, w := range widths {
+			if w > widths[widest] {
+				widest = i
 			}
-			row = row.Elem()
 		}
 
-		td = append(td, columnsInRow(row, t.showHidden))
+		if widths[widest] <= minColumnWidth {
+			break
+		}
+
+		widths[widest]--
+		total--
 	}
+}
 
-	return td, nil
+// truncateCells truncates the cells in rows that are too wide for their column with an ellipsis. Colors are removed
+// from truncated cells. Columns with a width of zero or less are left as is.
+func truncateCells(rows [][]string, widths []int) {
+	for _, row := range rows {
+		for i, cell := range row {
+			if widths[i] <= 0 || cellWidth(cell) <= widths[i] {
+				continue
+			}
+
+			lines := strings.Split(pterm.RemoveColorFromString(cell), "\n")
+			for j, line := range lines {
+				lines[j] = runewidth.Truncate(line, widths[i], "…")
+			}
+			row[i] = strings.Join(lines, "\n")
+		}
+	}
 }
 
-// extractHeaders returns a slice of header strings extracted from the struct fields of the provided value.
-func (t *Table) extractHeaders(v reflect.Value) ([]string, error) {
-	if v.Kind() == reflect.Pointer {
-		if v.IsNil() {
-			return nil, fmt.Errorf("nil pointer in sice at index 0")
+// padCells pads the lines of the cells in rows with spaces to the width of their column, so that the columns of tables
+// rendered separately line up. Cells in columns with the `align:"right"` tag are padded on the left.
+func padCells(rows [][]string, columns []tableColumn, widths []int) {
+	for _, row := range rows {
+		for i, cell := range row {
+			lines := strings.Split(cell, "\n")
+			for j, line := range lines {
+				w := runewidth.StringWidth(pterm.RemoveColorFromString(line))
+				if w >= widths[i] {
+					continue
+				}
+
+				if columns[i].alignRight {
+					lines[j] = strings.Repeat(" ", widths[i]-w) + line
+				} else {
+					lines[j] = line + strings.Repeat(" ", widths[i]-w)
+				}
+			}
+			row[i] = strings.Join(lines, "\n")
 		}
+	}
+}
 
-		v = v.Elem()
+// cellWidth returns the width of the widest line in a cell, as displayed in a terminal.
+func cellWidth(cell string) int {
+	width := 0
+	for line := range strings.SplitSeq(pterm.RemoveColorFromString(cell), "\n") {
+		width = max(width, runewidth.StringWidth(line))
 	}
+	return width
+}
+
+// rowSelection controls which columns and rows are extracted from the data passed to a renderer, and in which order
+// the rows are returned.
+type rowSelection struct {
+	// formatted is set when the values are formatted using the `format` and `color` field tags.
+	formatted bool
+
+	showHidden bool
+	wide       bool
+	columns    []string
+	sortBy     []string
+	filters    []string
+}
+
+// tableColumn is a column in the data passed to a renderer.
+type tableColumn struct {
+	heading string
+	hidden  bool
+	wide    bool
+
+	// width is the width of the column declared using the `width` field tag, or zero if not declared.
+	width int
+
+	// format, alignRight and color are set using the `format`, `align` and `color` field tags.
+	format     string
+	alignRight bool
+	color      string
 
-	if v.Kind() != reflect.Struct {
-		return nil, fmt.Errorf("value must be a struct, got %T", v.Interface())
+	// index is the index of the struct field, or the index in the string slice, holding the values of the column.
+	index int
+}
+
+// rowFilter is a parsed "heading=value" or "heading!=value" filter.
+type rowFilter struct {
+	column tableColumn
+	value  string
+	negate bool
+}
+
+// sortKey is a parsed sort key, where a "-" prefix means descending order.
+type sortKey struct {
+	column     tableColumn
+	descending bool
+}
+
+// extractRows converts the provided data, a slice of structs or a slice of string slices, into rows of strings. The
+// columns, filters and sorting set in sel are applied to the rows. The visible columns are returned along with the
+// rows, where the first row holds the headers.
+func extractRows(v any, sel rowSelection) ([]tableColumn, [][]string, error) {
+	columns, rows, err := extractColumns(v)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	headers := make([]string, 0)
-	for i := 0; i < v.NumField(); i++ {
-		field := v.Type().Field(i)
-		if !field.IsExported() {
-			continue
+	visible, err := sel.visibleColumns(columns)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	filters, err := sel.rowFilters(columns)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	keys, err := sel.sortKeys(columns)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rows = slices.DeleteFunc(rows, func(row reflect.Value) bool {
+		return !sel.matchesFilters(row, filters)
+	})
+	sortRows(rows, keys)
+
+	ret := [][]string{headings(visible)}
+	for _, row := range rows {
+		ret = append(ret, sel.rowCells(row, visible))
+	}
+
+	return visible, ret, nil
+}
+
+// matchesFilters reports whether the row matches all the provided filters.
+func (s rowSelection) matchesFilters(row reflect.Value, filters []rowFilter) bool {
+	for _, f := range filters {
+		if (pterm.RemoveColorFromString(s.cell(row, f.column)) == f.value) == f.negate {
+			return false
+		}
+	}
+	return true
+}
+
+// sortRows sorts the rows by the provided sort keys. The order of rows with equal keys is preserved.
+func sortRows(rows []reflect.Value, keys []sortKey) {
+	slices.SortStableFunc(rows, func(a, b reflect.Value) int {
+		for _, k := range keys {
+			c := compareValues(cellValue(a, k.column), cellValue(b, k.column))
+			if k.descending {
+				c = -c
+			}
+
+			if c != 0 {
+				return c
+			}
+		}
+		return 0
+	})
+}
+
+// headings returns the headings of the provided columns.
+func headings(columns []tableColumn) []string {
+	ret := make([]string, len(columns))
+	for i, col := range columns {
+		ret[i] = col.heading
+	}
+	return ret
+}
+
+// rowCells returns the string representation of the values of the provided columns in a row.
+func (s rowSelection) rowCells(row reflect.Value, columns []tableColumn) []string {
+	ret := make([]string, len(columns))
+	for i, col := range columns {
+		ret[i] = s.cell(row, col)
+	}
+	return ret
+}
+
+// cell returns the string representation of the value of the provided column in a row. When the selection is
+// formatted, values implementing [TableCell] are rendered using the interface, with the color tags in the returned
+// string colorized, and the `format` and `color` field tags are applied to other values. Color tags in other values are
+// left as is, since the values might hold data that happens to look like the tags.
+func (s rowSelection) cell(row reflect.Value, col tableColumn) string {
+	v := cellValue(row, col)
+	if !s.formatted {
+		return getStringValue(v)
+	}
+
+	if c, ok := asTableCell(v); ok {
+		return color.Colorize(c.FormatCell())
+	}
+
+	return colorCell(formatCell(v, col.format), col.color)
+}
+
+// extractColumns returns the columns and the rows of the provided data, a slice of structs or a slice of string slices.
+// Each row is either a struct value or a string slice, and the values in a row can be fetched using [cellValue].
+func extractColumns(v any) ([]tableColumn, []reflect.Value, error) {
+	vt := reflect.TypeOf(v)
+	d := reflect.ValueOf(v)
+	if vt == nil || vt.Kind() != reflect.Slice || d.Len() == 0 {
+		return nil, nil, fmt.Errorf("data must be a non-empty slice, got %T", v)
+	}
+
+	if elem := vt.Elem(); elem.Kind() == reflect.Slice && elem.Elem().Kind() == reflect.String {
+		d, ok := v.([][]string)
+		if !ok {
+			return nil, nil, fmt.Errorf("unable to convert data")
+		}
+
+		columns := make([]tableColumn, len(d[0]))
+		for i, heading := range d[0] {
+			columns[i] = tableColumn{heading: heading, index: i}
+		}
+
+		rows := make([]reflect.Value, 0, len(d)-1)
+		for _, row := range d[1:] {
+			rows = append(rows, reflect.ValueOf(row))
+		}
+
+		return columns, rows, nil
+	}
+
+	rows := make([]reflect.Value, 0, d.Len())
+	for i := 0; i < d.Len(); i++ {
+		row := d.Index(i)
+
+		if row.Kind() == reflect.Pointer {
+			if row.IsNil() {
+				return nil, nil, fmt.Errorf("nil pointer in slice at index %d", i)
+			}
+			row = row.Elem()
+		}
+
+		if row.Kind() != reflect.Struct {
+			return nil, nil, fmt.Errorf("value must be a struct, got %T", row.Interface())
 		}
 
-		if field.Tag.Get("hidden") == "true" && !t.showHidden {
+		rows = append(rows, row)
+	}
+
+	return extractHeaders(rows[0].Type()), rows, nil
+}
+
+// extractHeaders returns the columns extracted from the exported fields of the provided struct type.
+func extractHeaders(t reflect.Type) []tableColumn {
+	columns := make([]tableColumn, 0)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
 			continue
 		}
 
@@ -188,40 +561,174 @@ func (t *Table) extractHeaders(v reflect.Value) ([]string, error) {
 		if tag := field.Tag.Get("heading"); tag != "" {
 			heading = tag
 		}
-		headers = append(headers, heading)
+
+		// invalid widths are ignored, like the other tags with invalid values
+		width, _ := strconv.Atoi(field.Tag.Get("width"))
+
+		columns = append(columns, tableColumn{
+			heading:    heading,
+			hidden:     field.Tag.Get("hidden") == "true",
+			wide:       field.Tag.Get("wide") == "true",
+			width:      max(width, 0),
+			format:     field.Tag.Get("format"),
+			alignRight: field.Tag.Get("align") == "right",
+			color:      field.Tag.Get("color"),
+			index:      i,
+		})
+	}
+
+	return columns
+}
+
+// visibleColumns returns the columns to render. When columns have been selected, the selected columns are returned in
+// the selected order, otherwise all columns that are not hidden, and wide columns only when wide output is enabled.
+func (s rowSelection) visibleColumns(columns []tableColumn) ([]tableColumn, error) {
+	if len(s.columns) > 0 {
+		ret := make([]tableColumn, 0, len(s.columns))
+		for _, heading := range s.columns {
+			col, err := lookupColumn(columns, heading)
+			if err != nil {
+				return nil, err
+			}
+			ret = append(ret, col)
+		}
+		return ret, nil
 	}
 
-	if len(headers) == 0 {
+	ret := make([]tableColumn, 0)
+	for _, col := range columns {
+		if (!col.hidden || s.showHidden) && (!col.wide || s.wide) {
+			ret = append(ret, col)
+		}
+	}
+
+	if len(ret) == 0 {
 		return nil, fmt.Errorf("no visible fields in struct")
 	}
 
-	return headers, nil
+	return ret, nil
 }
 
-// columnsInRow returns a slice of strings representing the values of the exported fields (the columns) in the provided
-// struct value (the row).
-func columnsInRow(row reflect.Value, showHidden bool) []string {
-	fields := reflect.TypeOf(row.Interface())
-	values := reflect.ValueOf(row.Interface())
+// rowFilters parses the filters of the selection.
+func (s rowSelection) rowFilters(columns []tableColumn) ([]rowFilter, error) {
+	ret := make([]rowFilter, 0, len(s.filters))
+	for _, f := range s.filters {
+		heading, value, ok := strings.Cut(f, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid filter %q, must be in the format column=value or column!=value", f)
+		}
 
-	cols := make([]string, 0)
-	for i := range fields.NumField() {
-		if !fields.Field(i).IsExported() {
-			continue
+		heading, negate := strings.CutSuffix(heading, "!")
+		col, err := lookupColumn(columns, strings.TrimSpace(heading))
+		if err != nil {
+			return nil, err
 		}
 
-		if fields.Field(i).Tag.Get("hidden") == "true" && !showHidden {
-			continue
+		ret = append(ret, rowFilter{column: col, value: value, negate: negate})
+	}
+
+	return ret, nil
+}
+
+// sortKeys parses the sort keys of the selection.
+func (s rowSelection) sortKeys(columns []tableColumn) ([]sortKey, error) {
+	ret := make([]sortKey, 0, len(s.sortBy))
+	for _, key := range s.sortBy {
+		heading, descending := strings.CutPrefix(strings.TrimSpace(key), "-")
+		col, err := lookupColumn(columns, strings.TrimPrefix(heading, "+"))
+		if err != nil {
+			return nil, err
+		}
+
+		ret = append(ret, sortKey{column: col, descending: descending})
+	}
+
+	return ret, nil
+}
+
+// lookupColumn returns the column with the provided heading, ignoring case.
+func lookupColumn(columns []tableColumn, heading string) (tableColumn, error) {
+	headings := make([]string, len(columns))
+	for i, col := range columns {
+		if strings.EqualFold(col.heading, heading) {
+			return col, nil
 		}
+		headings[i] = col.heading
+	}
+
+	return tableColumn{}, fmt.Errorf("unknown column %q, must be one of: %s", heading, strings.Join(headings, ", "))
+}
 
-		cols = append(cols, getStringValue(values.Field(i)))
+// cellValue returns the value of the provided column in a row. An invalid value is returned if the row does not have a
+// value for the column.
+func cellValue(row reflect.Value, col tableColumn) reflect.Value {
+	if row.Kind() == reflect.Struct {
+		return row.Field(col.index)
 	}
 
-	return cols
+	if col.index < row.Len() {
+		return row.Index(col.index)
+	}
+
+	return reflect.Value{}
 }
 
-// getStringValue returns the string representation of the provided reflect.Value.
+// compareValues compares two values of a column. Numbers, booleans and timestamps are compared by value, other values
+// are compared by their string representation. Missing values, like nil pointers, are sorted first.
+func compareValues(a, b reflect.Value) int {
+	a, b = indirect(a), indirect(b)
+	if !a.IsValid() || !b.IsValid() {
+		return cmp.Compare(boolToInt(a.IsValid()), boolToInt(b.IsValid()))
+	}
+
+	if at, ok := a.Interface().(time.Time); ok {
+		if bt, ok := b.Interface().(time.Time); ok {
+			return at.Compare(bt)
+		}
+	}
+
+	if a.Kind() == b.Kind() {
+		switch a.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return cmp.Compare(a.Int(), b.Int())
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			return cmp.Compare(a.Uint(), b.Uint())
+		case reflect.Float32, reflect.Float64:
+			return cmp.Compare(a.Float(), b.Float())
+		case reflect.Bool:
+			return cmp.Compare(boolToInt(a.Bool()), boolToInt(b.Bool()))
+		}
+	}
+
+	return cmp.Compare(getStringValue(a), getStringValue(b))
+}
+
+// indirect dereferences pointers and interfaces, returning an invalid value for nil values.
+func indirect(v reflect.Value) reflect.Value {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+// boolToInt returns 1 for true and 0 for false.
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// getStringValue returns the string representation of the provided reflect.Value. Values implementing [fmt.Stringer],
+// including addressable values with a pointer receiver, are rendered using the interface.
 func getStringValue(v reflect.Value) string {
+	if s, ok := asStringer(v); ok {
+		return s.String()
+	}
+
 	if v.Kind() == reflect.Pointer {
 		v = v.Elem()
 	}
@@ -230,5 +737,5 @@ func getStringValue(v reflect.Value) string {
 		return ""
 	}
 
-	return color.Colorize(fmt.Sprint(v.Interface()))
+	return fmt.Sprint(v.Interface())
 }
@@ -0,0 +1,226 @@
+package output
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nais/naistrix/internal/color"
+)
+
+// TableCell can be implemented by the types of struct fields to fully control how their values are rendered in a
+// [Table]. The returned string can contain inline color tags, like "<error>failed</error>". Color tags are only
+// colorized in the strings returned by TableCell, and text that looks like the tags in other values is rendered as is.
+//
+// Values that do not implement TableCell are rendered using [fmt.Stringer] when implemented, and [fmt.Sprint]
+// otherwise, unless they are formatted using one of the following field tags:
+//
+//   - `format:"age"` renders a [time.Time] as the time passed since then, like "5m" or "3d4h", and a [time.Duration]
+//     the same way.
+//   - `format:"date"` renders a [time.Time] as a date, like "2006-01-02".
+//   - `format:"bytes"` renders a number as a size in bytes, like "512 B" or "1.5 MiB".
+//   - `format:"percent"` renders a number as a percentage, where floats are fractions, so 0.5 is rendered as "50%", and
+//     integers are percentages, so 50 is rendered as "50%".
+//   - `align:"right"` aligns the values and the heading of the column to the right, which is useful for numbers.
+//   - `color:"status"` colors values like "Running" and "Ready" green, "Pending" and "Unknown" yellow, and "Failed" and
+//     "Error" red. Any tag in the theme can be used instead of status, like `color:"muted"`, to color all values of
+//     the column.
+//
+// Formats are ignored for values of other types, and nil pointers and zero timestamps are rendered as empty cells.
+// TableCell, formats and colors only apply to tables, and values are left as is in for instance the csv output format.
+type TableCell interface {
+	FormatCell() string
+}
+
+// asTableCell returns the value as a [TableCell], if it implements the interface.
+func asTableCell(v reflect.Value) (TableCell, bool) {
+	return asInterface[TableCell](v)
+}
+
+// asStringer returns the value as a [fmt.Stringer], if it implements the interface.
+func asStringer(v reflect.Value) (fmt.Stringer, bool) {
+	return asInterface[fmt.Stringer](v)
+}
+
+// asInterface returns the value as an I, if the value, or a pointer to the value when it is addressable, implements the
+// interface. Nil pointers and interfaces are never returned.
+func asInterface[I any](v reflect.Value) (I, bool) {
+	var zero I
+	if !v.IsValid() || !v.CanInterface() {
+		return zero, false
+	}
+
+	if (v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface) && v.IsNil() {
+		return zero, false
+	}
+
+	if i, ok := v.Interface().(I); ok {
+		return i, true
+	}
+
+	if v.CanAddr() {
+		if i, ok := v.Addr().Interface().(I); ok {
+			return i, true
+		}
+	}
+
+	return zero, false
+}
+
+// formatCell formats the value using the format set with the `format` field tag.
+func formatCell(v reflect.Value, format string) string {
+	if format == "" {
+		return getStringValue(v)
+	}
+
+	iv := indirect(v)
+	if !iv.IsValid() {
+		return ""
+	}
+
+	switch value := iv.Interface().(type) {
+	case time.Time:
+		switch {
+		case format != "age" && format != "date":
+			return getStringValue(v)
+		case value.IsZero():
+			return ""
+		case format == "age":
+			return formatAge(time.Since(value))
+		default:
+			return value.Format(time.DateOnly)
+		}
+	case time.Duration:
+		if format == "age" {
+			return formatAge(value)
+		}
+		return getStringValue(v)
+	}
+
+	var f float64
+	var isFloat bool
+	switch iv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		f = float64(iv.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		f = float64(iv.Uint())
+	case reflect.Float32, reflect.Float64:
+		f, isFloat = iv.Float(), true
+	default:
+		return getStringValue(v)
+	}
+
+	switch format {
+	case "bytes":
+		return formatBytes(f)
+	case "percent":
+		if isFloat {
+			f *= 100
+		}
+		return formatDecimal(f) + "%"
+	}
+
+	return getStringValue(v)
+}
+
+// formatAge formats a duration in a compact form, where the precision decreases as the duration grows, like "90s",
+// "5m30s", "2h15m", "3d4h" and "2y". Negative durations are formatted as their absolute value.
+func formatAge(d time.Duration) string {
+	seconds := int64(math.Abs(d.Seconds()))
+	minutes, hours := seconds/60, seconds/3600
+	days, years := hours/24, hours/(24*365)
+
+	switch {
+	case seconds < 120:
+		return fmt.Sprintf("%ds", seconds)
+	case minutes < 10:
+		return withRemainder(minutes, "m", seconds%60, "s")
+	case minutes < 3*60:
+		return fmt.Sprintf("%dm", minutes)
+	case hours < 8:
+		return withRemainder(hours, "h", minutes%60, "m")
+	case hours < 48:
+		return fmt.Sprintf("%dh", hours)
+	case days < 8:
+		return withRemainder(days, "d", hours%24, "h")
+	case years < 2:
+		return fmt.Sprintf("%dd", days)
+	case years < 8:
+		return withRemainder(years, "y", days%365, "d")
+	default:
+		return fmt.Sprintf("%dy", years)
+	}
+}
+
+// withRemainder formats a value and its remainder in a smaller unit, leaving out the remainder when it is zero.
+func withRemainder(value int64, unit string, remainder int64, remainderUnit string) string {
+	if remainder == 0 {
+		return fmt.Sprintf("%d%s", value, unit)
+	}
+	return fmt.Sprintf("%d%s%d%s", value, unit, remainder, remainderUnit)
+}
+
+// byteUnits are the binary units used when formatting sizes in bytes.
+var byteUnits = []string{"B", "KiB", "MiB", "GiB", "TiB", "PiB", "EiB"}
+
+// formatBytes formats a size in bytes using binary units, like "512 B" or "1.5 MiB".
+func formatBytes(size float64) string {
+	unit := 0
+	for math.Abs(size) >= 1024 && unit < len(byteUnits)-1 {
+		size /= 1024
+		unit++
+	}
+
+	return formatDecimal(size) + " " + byteUnits[unit]
+}
+
+// formatDecimal formats a number with at most one decimal, leaving out the decimal when it is zero.
+func formatDecimal(f float64) string {
+	return strings.TrimSuffix(strconv.FormatFloat(f, 'f', 1, 64), ".0")
+}
+
+// statusColors maps lower case status values to the tags used to color them when using the `color:"status"` field tag.
+var statusColors = map[string]string{
+	"running":          "success",
+	"ready":            "success",
+	"healthy":          "success",
+	"succeeded":        "success",
+	"success":          "success",
+	"completed":        "success",
+	"active":           "success",
+	"available":        "success",
+	"ok":               "success",
+	"pending":          "warn",
+	"progressing":      "warn",
+	"starting":         "warn",
+	"terminating":      "warn",
+	"waiting":          "warn",
+	"degraded":         "warn",
+	"warning":          "warn",
+	"unknown":          "warn",
+	"failed":           "error",
+	"failure":          "error",
+	"error":            "error",
+	"unhealthy":        "error",
+	"crashloopbackoff": "error",
+	"imagepullbackoff": "error",
+	"errimagepull":     "error",
+	"oomkilled":        "error",
+}
+
+// colorCell colors the cell using the color tag set with the `color` field tag. When the tag is "status", the color tag
+// is chosen based on the value of the cell, and values that are not known statuses are left as is.
+func colorCell(cell, tag string) string {
+	if tag == "status" {
+		tag = statusColors[strings.ToLower(strings.TrimSpace(cell))]
+	}
+
+	if tag == "" || cell == "" {
+		return cell
+	}
+
+	return color.Sprint(tag, cell)
+}
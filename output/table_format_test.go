@@ -0,0 +1,215 @@
+package output_test
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nais/naistrix/output"
+	"github.com/pterm/pterm"
+)
+
+type version struct {
+	major, minor int
+}
+
+func (v *version) String() string {
+	return fmt.Sprintf("v%d.%d", v.major, v.minor)
+}
+
+type replicas struct {
+	ready, desired int
+}
+
+func (r replicas) FormatCell() string {
+	return fmt.Sprintf("<info>%d/%d</info>", r.ready, r.desired)
+}
+
+func TestTable_Format(t *testing.T) {
+	pterm.DisableStyling()
+	defer pterm.EnableStyling()
+
+	tests := []struct {
+		name     string
+		data     any
+		expected string
+	}{
+		{
+			name: "age",
+			data: []struct {
+				Created time.Time     `format:"age"`
+				Uptime  time.Duration `format:"age"`
+			}{
+				{Created: time.Now().Add(-90 * time.Minute), Uptime: 75 * time.Second},
+				{Created: time.Now().Add(-76 * time.Hour), Uptime: 5*time.Minute + 30*time.Second},
+				{Uptime: 3*365*24*time.Hour + 24*time.Hour},
+			},
+			expected: "Created | Uptime\n" +
+				"----------------\n" +
+				"90m     | 75s   \n" +
+				"3d4h    | 5m30s \n" +
+				"        | 3y1d  \n",
+		},
+		{
+			name: "date",
+			data: []struct {
+				Created *time.Time `format:"date"`
+			}{
+				{Created: new(time.Date(2024, 5, 17, 13, 37, 0, 0, time.UTC))},
+				{},
+			},
+			expected: "Created   \n" +
+				"----------\n" +
+				"2024-05-17\n" +
+				"          \n",
+		},
+		{
+			name: "bytes and percent",
+			data: []struct {
+				Size  int64   `format:"bytes"`
+				Usage float64 `format:"percent"`
+				Quota int     `format:"percent"`
+			}{
+				{Size: 512, Usage: 0.425, Quota: 50},
+				{Size: 1536, Usage: 1, Quota: 100},
+				{Size: 10 * 1024 * 1024 * 1024, Usage: 0, Quota: 0},
+			},
+			expected: "Size    | Usage | Quota\n" +
+				"-----------------------\n" +
+				"512 B   | 42.5% | 50%  \n" +
+				"1.5 KiB | 100%  | 100% \n" +
+				"10 GiB  | 0%    | 0%   \n",
+		},
+		{
+			name: "formats are ignored for unsupported types",
+			data: []struct {
+				Name string `format:"bytes"`
+			}{
+				{Name: "api"},
+			},
+			expected: "Name\n" +
+				"----\n" +
+				"api \n",
+		},
+		{
+			name: "right alignment",
+			data: []struct {
+				Name     string
+				Restarts int `align:"right"`
+			}{
+				{Name: "api", Restarts: 3},
+				{Name: "web", Restarts: 12345},
+			},
+			expected: "Name | Restarts\n" +
+				"---------------\n" +
+				"api  |        3\n" +
+				"web  |    12345\n",
+		},
+		{
+			name: "stringer and table cell",
+			data: []struct {
+				Version  version
+				Replicas replicas
+			}{
+				{Version: version{major: 1, minor: 2}, Replicas: replicas{ready: 1, desired: 2}},
+			},
+			expected: "Version | Replicas\n" +
+				"------------------\n" +
+				"v1.2    | 1/2     \n",
+		},
+		{
+			name: "color tags in values are rendered as is",
+			data: []struct {
+				Name     string
+				Replicas replicas
+			}{
+				{Name: "<bold>api</bold>", Replicas: replicas{ready: 2, desired: 2}},
+			},
+			expected: "Name             | Replicas\n" +
+				"---------------------------\n" +
+				"<bold>api</bold> | 2/2     \n",
+		},
+		{
+			name: "status colors are removed when styling is disabled",
+			data: []struct {
+				Status string `color:"status"`
+			}{
+				{Status: "Running"},
+				{Status: "Something"},
+			},
+			expected: "Status   \n" +
+				"---------\n" +
+				"Running  \n" +
+				"Something\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := output.NewTable(&buf).Render(tt.data); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if actual := buf.String(); actual != tt.expected {
+				t.Fatalf("expected %q, got: %q", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestTable_StatusColors(t *testing.T) {
+	data := []struct {
+		Status string `color:"status"`
+		Note   string `color:"muted"`
+		Ready  bool   `color:"status"`
+	}{
+		{Status: "Running", Note: "note", Ready: true},
+		{Status: "pending", Note: "note"},
+		{Status: "CrashLoopBackOff", Note: "note"},
+		{Status: "Something", Note: "note"},
+	}
+
+	var buf bytes.Buffer
+	if err := output.NewTable(&buf).Render(data); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	actual := buf.String()
+	for _, expected := range []string{
+		pterm.FgGreen.Sprint("Running"),
+		pterm.FgYellow.Sprint("pending"),
+		pterm.FgLightRed.Sprint("CrashLoopBackOff"),
+		pterm.FgGray.Sprint("note"),
+	} {
+		if !strings.Contains(actual, expected) {
+			t.Errorf("expected output to contain %q, got: %q", expected, actual)
+		}
+	}
+
+	for _, unexpected := range []string{"\x1b[32mSomething", "\x1b[32mtrue", "\x1b[91mfalse", "<"} {
+		if strings.Contains(actual, unexpected) {
+			t.Errorf("expected unknown statuses and booleans to be left as is, got: %q", actual)
+		}
+	}
+}
+
+func TestCSV_IgnoresTableFormats(t *testing.T) {
+	data := []struct {
+		Size   int    `format:"bytes"`
+		Status string `color:"status"`
+	}{
+		{Size: 2048, Status: "Running"},
+	}
+
+	var buf bytes.Buffer
+	if err := output.NewCSV(&buf).Render(data); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if expected, actual := "Size,Status\n2048,Running\n", buf.String(); actual != expected {
+		t.Fatalf("expected %q, got: %q", expected, actual)
+	}
+}
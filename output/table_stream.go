@@ -0,0 +1,190 @@
+package output
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+)
+
+// TableStream writes the rows of a [Table] as they arrive, instead of rendering all rows at once. Use [Table.Stream] to
+// construct one. A TableStream is not safe for concurrent use.
+type TableStream struct {
+	table   *Table
+	typ     reflect.Type
+	columns []tableColumn
+	filters []rowFilter
+	keys    []sortKey
+	widths  []int
+	limits  []int
+	pending []reflect.Value
+	started bool
+	closed  bool
+}
+
+// Stream returns a [TableStream] that writes rows to the table one at a time, which lets end-users see the first rows
+// before all rows are available, for instance when paging through a large number of items. The rows must be structs,
+// or pointers to structs, of the same type, and the columns are extracted from the struct type as described in
+// [Table.Render].
+//
+// As the widths of the columns must be known before the first row is written, the rows are buffered until a sample of
+// rows has been collected, see [TableWithSampleSize]. The header and the sample are then written, and the following
+// rows are written as they arrive. The widths of the columns are fixed from the sample, unless declared using the
+// `width` field tag, and cells that are too wide for their column are truncated with an ellipsis. When the widths of
+// all columns are declared, nothing is buffered. The last column is only truncated when its width is declared or the
+// table has a maximum width, as nothing comes after it.
+//
+// Filters set with TableWithFilters are applied to each row as it arrives. As sorting requires all rows, nothing is
+// written until the stream is closed when the rows are sorted using TableWithSortBy.
+//
+// The stream must be closed using [TableStream.Close] to write any buffered rows.
+func (t *Table) Stream() *TableStream {
+	return &TableStream{table: t}
+}
+
+// Write adds a row to the table. The row is buffered until the widths of the columns are known, and written
+// immediately after that. If the writer of the table buffers output, it is flushed after the row has been written.
+func (s *TableStream) Write(row any) error {
+	if s.closed {
+		return fmt.Errorf("table stream is closed")
+	}
+
+	rv := reflect.ValueOf(row)
+	if rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return fmt.Errorf("nil pointer passed to table stream")
+		}
+		rv = rv.Elem()
+	}
+
+	if rv.Kind() != reflect.Struct {
+		return fmt.Errorf("value must be a struct, got %T", row)
+	}
+
+	if s.typ == nil {
+		if err := s.init(rv.Type()); err != nil {
+			return err
+		}
+	} else if rv.Type() != s.typ {
+		return fmt.Errorf("value must be a %s, got %T", s.typ, row)
+	}
+
+	if !s.table.selection.matchesFilters(rv, s.filters) {
+		return nil
+	}
+
+	if s.started {
+		return s.writeRow(s.table.selection.rowCells(rv, s.columns))
+	}
+
+	s.pending = append(s.pending, rv)
+	if len(s.keys) == 0 && (len(s.pending) >= s.table.sampleSize || !slices.Contains(s.widths, 0)) {
+		return s.start()
+	}
+
+	return nil
+}
+
+// Close writes the rows that are still buffered, along with the header if it has not been written yet. Nothing is
+// written if no rows have been added to the stream. Closing a closed stream does nothing.
+func (s *TableStream) Close() error {
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+
+	if s.typ == nil {
+		return nil
+	}
+
+	if !s.started {
+		if err := s.start(); err != nil {
+			return err
+		}
+	}
+
+	if s.table.bottomMargin {
+		if _, err := s.table.writer.Write([]byte{'\n'}); err != nil {
+			return err
+		}
+	}
+
+	return flush(s.table.writer)
+}
+
+// init extracts the columns, filters and sort keys of the table from the struct type of the rows.
+func (s *TableStream) init(typ reflect.Type) error {
+	columns := extractHeaders(typ)
+	visible, err := s.table.selection.visibleColumns(columns)
+	if err != nil {
+		return err
+	}
+
+	filters, err := s.table.selection.rowFilters(columns)
+	if err != nil {
+		return err
+	}
+
+	keys, err := s.table.selection.sortKeys(columns)
+	if err != nil {
+		return err
+	}
+
+	s.typ = typ
+	s.columns = visible
+	s.filters = filters
+	s.keys = keys
+	s.widths = make([]int, len(visible))
+	for i, col := range visible {
+		s.widths[i] = col.width
+	}
+
+	return nil
+}
+
+// start fixes the widths of the columns from the buffered rows, and writes the header and the buffered rows.
+func (s *TableStream) start() error {
+	sortRows(s.pending, s.keys)
+
+	rows := [][]string{headings(s.columns)}
+	for _, row := range s.pending {
+		rows = append(rows, s.table.selection.rowCells(row, s.columns))
+	}
+	s.pending = nil
+
+	s.widths = s.table.columnWidths(s.columns, rows)
+	s.limits = slices.Clone(s.widths)
+	if last := len(s.columns) - 1; s.columns[last].width <= 0 && s.table.maxWidth <= 0 {
+		s.limits[last] = 0
+	}
+	s.started = true
+
+	b, err := s.table.render(rows, s.columns, s.widths, s.limits, true)
+	if err != nil {
+		return err
+	}
+
+	if s.table.topMargin {
+		b = append([]byte{'\n'}, b...)
+	}
+
+	return s.write(b)
+}
+
+// writeRow writes a single row, using the widths of the columns fixed by start.
+func (s *TableStream) writeRow(cells []string) error {
+	b, err := s.table.render([][]string{cells}, s.columns, s.widths, s.limits, false)
+	if err != nil {
+		return err
+	}
+
+	return s.write(b)
+}
+
+// write writes the rendered rows followed by a newline, and flushes the writer of the table if it buffers output.
+func (s *TableStream) write(b []byte) error {
+	if _, err := s.table.writer.Write(append(b, '\n')); err != nil {
+		return err
+	}
+
+	return flush(s.table.writer)
+}
@@ -0,0 +1,249 @@
+package output_test
+
+import (
+	"bytes"
+	"errors"
+	"iter"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/nais/naistrix/output"
+	"github.com/pterm/pterm"
+)
+
+type streamRow struct {
+	Name   string
+	Status string
+}
+
+func TestTableStream(t *testing.T) {
+	pterm.DisableStyling()
+	defer pterm.EnableStyling()
+
+	t.Run("header and sample are written when the sample is full", func(t *testing.T) {
+		var buf bytes.Buffer
+		stream := output.NewTable(&buf, output.TableWithSampleSize(2)).Stream()
+
+		if err := stream.Write(streamRow{Name: "api", Status: "Running"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if buf.Len() != 0 {
+			t.Fatalf("expected rows to be buffered, got: %q", buf.String())
+		}
+
+		if err := stream.Write(&streamRow{Name: "web", Status: "Failed"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		expected := "Name | Status \n" +
+			"--------------\n" +
+			"api  | Running\n" +
+			"web  | Failed \n"
+		if actual := buf.String(); actual != expected {
+			t.Fatalf("expected %q, got: %q", expected, actual)
+		}
+
+		if err := stream.Write(streamRow{Name: "frontend", Status: "Pending for a long time"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if err := stream.Close(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		expected += "fro… | Pending for a long time\n"
+		if actual := buf.String(); actual != expected {
+			t.Fatalf("expected %q, got: %q", expected, actual)
+		}
+	})
+
+	t.Run("declared widths", func(t *testing.T) {
+		var buf bytes.Buffer
+		stream := output.NewTable(&buf).Stream()
+
+		type row struct {
+			Name   string `width:"6"`
+			Status string `width:"7"`
+		}
+
+		if err := stream.Write(row{Name: "api", Status: "Running"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		expected := "Name   | Status \n" +
+			"----------------\n" +
+			"api    | Running\n"
+		if actual := buf.String(); actual != expected {
+			t.Fatalf("expected %q, got: %q", expected, actual)
+		}
+
+		if err := stream.Write(row{Name: "frontend", Status: "CrashLoopBackOff"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		expected += "front… | CrashL…\n"
+		if actual := buf.String(); actual != expected {
+			t.Fatalf("expected %q, got: %q", expected, actual)
+		}
+	})
+
+	t.Run("close writes buffered rows", func(t *testing.T) {
+		var buf bytes.Buffer
+		stream := output.NewTable(&buf, output.TableWithMargins()).Stream()
+
+		if err := stream.Write(streamRow{Name: "api", Status: "Running"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if err := stream.Close(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		expected := "\nName | Status \n" +
+			"--------------\n" +
+			"api  | Running\n\n"
+		if actual := buf.String(); actual != expected {
+			t.Fatalf("expected %q, got: %q", expected, actual)
+		}
+
+		if err := stream.Write(streamRow{}); err == nil || !strings.Contains(err.Error(), "closed") {
+			t.Fatalf("expected error about closed stream, got: %v", err)
+		}
+	})
+
+	t.Run("close without rows", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := output.NewTable(&buf).Stream().Close(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if buf.Len() != 0 {
+			t.Fatalf("expected no output, got: %q", buf.String())
+		}
+	})
+
+	t.Run("filters and sorting", func(t *testing.T) {
+		var buf bytes.Buffer
+		stream := output.NewTable(
+			&buf,
+			output.TableWithSampleSize(1),
+			output.TableWithFilters("status!=Failed"),
+			output.TableWithSortBy("-name"),
+		).Stream()
+
+		for _, row := range []streamRow{
+			{Name: "api", Status: "Running"},
+			{Name: "worker", Status: "Failed"},
+			{Name: "web", Status: "Running"},
+		} {
+			if err := stream.Write(row); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+
+		if buf.Len() != 0 {
+			t.Fatalf("expected sorted rows to be buffered until closed, got: %q", buf.String())
+		}
+
+		if err := stream.Close(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		expected := "Name | Status \n" +
+			"--------------\n" +
+			"web  | Running\n" +
+			"api  | Running\n"
+		if actual := buf.String(); actual != expected {
+			t.Fatalf("expected %q, got: %q", expected, actual)
+		}
+	})
+
+	t.Run("invalid rows", func(t *testing.T) {
+		var buf bytes.Buffer
+		stream := output.NewTable(&buf).Stream()
+
+		if err := stream.Write("some data"); err == nil || !strings.Contains(err.Error(), "must be a struct") {
+			t.Fatalf("expected error about structs, got: %v", err)
+		}
+
+		if err := stream.Write(streamRow{}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if err := stream.Write(struct{ Other string }{}); err == nil || !strings.Contains(err.Error(), "must be a") {
+			t.Fatalf("expected error about the row type, got: %v", err)
+		}
+	})
+}
+
+func TestTable_RenderSequence(t *testing.T) {
+	pterm.DisableStyling()
+	defer pterm.EnableStyling()
+
+	rows := []streamRow{
+		{Name: "api", Status: "Running"},
+		{Name: "web", Status: "Failed"},
+	}
+
+	tests := []struct {
+		name          string
+		data          any
+		expected      string
+		expectedError string
+	}{
+		{
+			name: "sequence",
+			data: slices.Values(rows),
+			expected: "Name | Status \n" +
+				"--------------\n" +
+				"api  | Running\n" +
+				"web  | Failed \n",
+		},
+		{
+			name: "sequence with error",
+			data: iter.Seq2[streamRow, error](func(yield func(streamRow, error) bool) {
+				if !yield(rows[0], nil) {
+					return
+				}
+				yield(streamRow{}, errors.New("page failed"))
+			}),
+			expected: "Name | Status \n" +
+				"--------------\n" +
+				"api  | Running\n",
+			expectedError: "page failed",
+		},
+		{
+			name: "empty sequence",
+			data: slices.Values([]streamRow{}),
+			expected: "Name | Status\n" +
+				"-------------\n",
+		},
+		{
+			name: "sequence of string slices",
+			data: slices.Values([][]string{{"Name"}, {"api"}}),
+			expected: "Name\n" +
+				"----\n" +
+				"api \n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := output.NewTable(&buf, output.TableWithSampleSize(1)).Render(tt.data)
+			if tt.expectedError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.expectedError) {
+					t.Fatalf("expected error containing %q, got: %v", tt.expectedError, err)
+				}
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if actual := buf.String(); actual != tt.expected {
+				t.Fatalf("expected %q, got: %q", tt.expected, actual)
+			}
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/nais/naistrix/output"
 	"github.com/pterm/pterm"
@@ -142,3 +143,195 @@ func TestTable_Margins(t *testing.T) {
 		})
 	}
 }
+
+func TestTable_Selection(t *testing.T) {
+	pterm.DisableStyling()
+	defer pterm.EnableStyling()
+
+	type app struct {
+		Name    string
+		Status  string
+		Age     time.Duration
+		Created time.Time
+		Team    *string `hidden:"true"`
+	}
+
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	data := []app{
+		{Name: "b", Status: "running", Age: 90 * time.Second, Created: now.Add(time.Hour), Team: new("x")},
+		{Name: "a", Status: "failing", Age: 10 * time.Minute, Created: now},
+		{Name: "c", Status: "running", Age: 5 * time.Second, Created: now.Add(-time.Hour), Team: new("y")},
+	}
+
+	tests := []struct {
+		name          string
+		data          any
+		opts          []output.TableOptionFunc
+		expected      string
+		errorContains string
+	}{
+		{
+			name:     "select columns",
+			data:     data,
+			opts:     []output.TableOptionFunc{output.TableWithColumns("status", "NAME")},
+			expected: "Status  | Name\n--------------\nrunning | b   \nfailing | a   \nrunning | c   \n",
+		},
+		{
+			name:     "select hidden column",
+			data:     data,
+			opts:     []output.TableOptionFunc{output.TableWithColumns("name", "team")},
+			expected: "Name | Team\n-----------\nb    | x   \na    |     \nc    | y   \n",
+		},
+		{
+			name:     "sort by duration descending",
+			data:     data,
+			opts:     []output.TableOptionFunc{output.TableWithColumns("name"), output.TableWithSortBy("-age")},
+			expected: "Name\n----\na   \nb   \nc   \n",
+		},
+		{
+			name:     "sort by time",
+			data:     data,
+			opts:     []output.TableOptionFunc{output.TableWithColumns("name"), output.TableWithSortBy("created")},
+			expected: "Name\n----\nc   \na   \nb   \n",
+		},
+		{
+			name:     "sort by multiple columns",
+			data:     data,
+			opts:     []output.TableOptionFunc{output.TableWithColumns("name"), output.TableWithSortBy("status", "-name")},
+			expected: "Name\n----\na   \nc   \nb   \n",
+		},
+		{
+			name:     "sort nil pointers first",
+			data:     data,
+			opts:     []output.TableOptionFunc{output.TableWithColumns("name"), output.TableWithSortBy("-team")},
+			expected: "Name\n----\nc   \nb   \na   \n",
+		},
+		{
+			name:     "filter",
+			data:     data,
+			opts:     []output.TableOptionFunc{output.TableWithColumns("name"), output.TableWithFilters("status=running", "name!=c")},
+			expected: "Name\n----\nb   \n",
+		},
+		{
+			name:     "filter removing all rows",
+			data:     data,
+			opts:     []output.TableOptionFunc{output.TableWithColumns("name"), output.TableWithFilters("status=unknown")},
+			expected: "Name\n----\n",
+		},
+		{
+			name: "string slices",
+			data: [][]string{
+				{"Name", "Status"},
+				{"b", "running"},
+				{"a", "failing"},
+			},
+			opts:     []output.TableOptionFunc{output.TableWithColumns("name"), output.TableWithSortBy("name")},
+			expected: "Name\n----\na   \nb   \n",
+		},
+		{
+			name:          "unknown column",
+			data:          data,
+			opts:          []output.TableOptionFunc{output.TableWithColumns("foo")},
+			errorContains: `unknown column "foo", must be one of: Name, Status, Age, Created, Team`,
+		},
+		{
+			name:          "invalid filter",
+			data:          data,
+			opts:          []output.TableOptionFunc{output.TableWithFilters("status")},
+			errorContains: `invalid filter "status"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := output.NewTable(&buf, tt.opts...).Render(tt.data)
+			if tt.errorContains != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errorContains) {
+					t.Fatalf("expected error to contain %q, got: %v", tt.errorContains, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if actual := buf.String(); actual != tt.expected {
+				t.Fatalf("expected %q, got: %q", tt.expected, actual)
+			}
+		})
+	}
+}
+
+// markup is a string with inline color tags, which are only colorized in tables when using [output.TableCell].
+type markup string
+
+func (m markup) FormatCell() string {
+	return string(m)
+}
+
+func TestTable_Width(t *testing.T) {
+	pterm.DisableStyling()
+	defer pterm.EnableStyling()
+
+	data := []struct {
+		Name        string
+		Description markup
+		Image       string `wide:"true"`
+	}{
+		{Name: "frontend", Description: "The frontend of the <info>application</info>", Image: "frontend:1.0"},
+		{Name: "api", Description: "Backend", Image: "api:2.0"},
+	}
+
+	tests := []struct {
+		name     string
+		opts     []output.TableOptionFunc
+		expected string
+	}{
+		{
+			name: "wide columns are left out by default",
+			expected: "Name     | Description                    \n" +
+				"------------------------------------------\n" +
+				"frontend | The frontend of the application\n" +
+				"api      | Backend                        \n",
+		},
+		{
+			name: "wide columns",
+			opts: []output.TableOptionFunc{output.TableWithWideColumns()},
+			expected: "Name     | Description                     | Image       \n" +
+				"---------------------------------------------------------\n" +
+				"frontend | The frontend of the application | frontend:1.0\n" +
+				"api      | Backend                         | api:2.0     \n",
+		},
+		{
+			name: "truncate the widest columns",
+			opts: []output.TableOptionFunc{output.TableWithWideColumns(), output.TableWithMaxWidth(40)},
+			expected: "Name     | Description    | Image       \n" +
+				"----------------------------------------\n" +
+				"frontend | The frontend … | frontend:1.0\n" +
+				"api      | Backend        | api:2.0     \n",
+		},
+		{
+			name: "wide enough",
+			opts: []output.TableOptionFunc{output.TableWithMaxWidth(100)},
+			expected: "Name     | Description                    \n" +
+				"------------------------------------------\n" +
+				"frontend | The frontend of the application\n" +
+				"api      | Backend                        \n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := output.NewTable(&buf, tt.opts...).Render(data); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if actual := buf.String(); actual != tt.expected {
+				t.Fatalf("expected %q, got: %q", tt.expected, actual)
+			}
+		})
+	}
+}
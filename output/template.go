@@ -0,0 +1,158 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"text/template"
+	"unicode/utf8"
+)
+
+// TemplateError is returned when a template can not be parsed or executed.
+type TemplateError struct {
+	// Template is the invalid template.
+	Template string
+
+	// Err is the underlying error from the template engine.
+	Err error
+}
+
+// Error returns a description of the invalid template.
+func (e *TemplateError) Error() string {
+	return fmt.Sprintf("invalid template %q: %v", e.Template, e.Err)
+}
+
+// Unwrap returns the underlying error from the template engine.
+func (e *TemplateError) Unwrap() error {
+	return e.Err
+}
+
+// TemplateOptionFunc is a function that can be used to configure the [Template] renderer.
+type TemplateOptionFunc func(*Template)
+
+// TemplateWithFuncs can be used to add functions to the template, in addition to the ones listed in [NewTemplate].
+// Functions with the same name as a built-in function replace the built-in one.
+func TemplateWithFuncs(funcs template.FuncMap) TemplateOptionFunc {
+	return func(t *Template) {
+		for name, fn := range funcs {
+			t.funcs[name] = fn
+		}
+	}
+}
+
+// Template is a renderer that executes a [text/template] against values, and writes the result to an [io.Writer],
+// similar to the go-template output format in docker and kubectl. Use [NewTemplate] to construct one.
+type Template struct {
+	text   string
+	funcs  template.FuncMap
+	writer io.Writer
+}
+
+// NewTemplate creates a new [Template] renderer that will write to the provided [io.Writer], using the provided
+// template text. The renderer can be configured using the optional [TemplateOptionFunc] arguments.
+//
+// In addition to the functions built into [text/template], the template has access to the following functions:
+//
+//	json     encodes a value as JSON: {{json .}}
+//	upper    converts a string to upper case: {{upper .Name}}
+//	lower    converts a string to lower case: {{lower .Name}}
+//	join     joins the elements of a slice using a separator: {{join ", " .Tags}}
+//	default  returns a default value if the value is empty: {{default "none" .Owner}}
+//	pad      pads a value with spaces to a minimum width, like a table column: {{pad 20 .Name}}
+//
+// The functions accept the value as the last argument, which means they can be used in pipelines as well, like for
+// instance {{.Owner | default "none" | upper}}.
+func NewTemplate(w io.Writer, text string, opts ...TemplateOptionFunc) *Template {
+	t := &Template{
+		text:   text,
+		funcs:  templateFuncs(),
+		writer: w,
+	}
+
+	for _, opt := range opts {
+		opt(t)
+	}
+
+	return t
+}
+
+// Validate checks that the template can be parsed, and returns a [*TemplateError] if not. Errors that occur when
+// executing the template, like referring to missing fields, are only returned by [Template.Render]. This method
+// satisfies the [Validator] interface.
+func (t *Template) Validate() error {
+	_, err := t.parse()
+	return err
+}
+
+// Render executes the template with v as the data, and writes the result to the configured [io.Writer]. If the template
+// can not be parsed or executed, a [*TemplateError] is returned, and nothing is written.
+func (t *Template) Render(v any) error {
+	tmpl, err := t.parse()
+	if err != nil {
+		return err
+	}
+
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, v); err != nil {
+		return &TemplateError{Template: t.text, Err: err}
+	}
+
+	_, err = io.WriteString(t.writer, sb.String())
+	return err
+}
+
+// parse parses the template, including the helper functions.
+func (t *Template) parse() (*template.Template, error) {
+	tmpl, err := template.New("output").Funcs(t.funcs).Parse(t.text)
+	if err != nil {
+		return nil, &TemplateError{Template: t.text, Err: err}
+	}
+	return tmpl, nil
+}
+
+// templateFuncs returns the helper functions available to all templates.
+func templateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"json": func(v any) (string, error) {
+			b, err := json.Marshal(v)
+			if err != nil {
+				return "", err
+			}
+			return string(b), nil
+		},
+		"upper": strings.ToUpper,
+		"lower": strings.ToLower,
+		"join": func(sep string, v any) (string, error) {
+			rv := reflect.ValueOf(v)
+			if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+				return "", fmt.Errorf("join: expected a slice, got %T", v)
+			}
+
+			s := make([]string, rv.Len())
+			for i := range rv.Len() {
+				s[i] = fmt.Sprint(rv.Index(i).Interface())
+			}
+			return strings.Join(s, sep), nil
+		},
+		"default": func(def, v any) any {
+			if v == nil || reflect.ValueOf(v).IsZero() {
+				return def
+			}
+
+			rv := reflect.ValueOf(v)
+			if (rv.Kind() == reflect.Slice || rv.Kind() == reflect.Map) && rv.Len() == 0 {
+				return def
+			}
+			return v
+		},
+		"pad": func(width int, v any) string {
+			s := fmt.Sprint(v)
+			if n := utf8.RuneCountInString(s); n < width {
+				s += strings.Repeat(" ", width-n)
+			}
+			return s
+		},
+	}
+}
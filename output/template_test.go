@@ -0,0 +1,164 @@
+package output_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/nais/naistrix/output"
+)
+
+func TestTemplate(t *testing.T) {
+	type app struct {
+		Name  string   `json:"name"`
+		Owner string   `json:"owner,omitempty"`
+		Tags  []string `json:"tags"`
+	}
+
+	apps := []app{
+		{Name: "frontend", Owner: "team-a", Tags: []string{"web", "public"}},
+		{Name: "api", Tags: []string{}},
+	}
+
+	tests := []struct {
+		name     string
+		template string
+		opts     []output.TemplateOptionFunc
+		data     any
+		expected string
+	}{
+		{
+			name:     "range over slice",
+			template: `{{range .}}{{.Name}}{{"\n"}}{{end}}`,
+			data:     apps,
+			expected: "frontend\napi\n",
+		},
+		{
+			name:     "json",
+			template: `{{json (index . 0)}}`,
+			data:     apps,
+			expected: `{"name":"frontend","owner":"team-a","tags":["web","public"]}`,
+		},
+		{
+			name:     "upper and lower",
+			template: `{{upper .}} {{lower .}}`,
+			data:     "Mixed",
+			expected: "MIXED mixed",
+		},
+		{
+			name:     "join",
+			template: `{{range .}}{{join "," .Tags}};{{end}}`,
+			data:     apps,
+			expected: "web,public;;",
+		},
+		{
+			name:     "default in pipeline",
+			template: `{{range .}}{{.Owner | default "none" | upper}} {{end}}`,
+			data:     apps,
+			expected: "TEAM-A NONE ",
+		},
+		{
+			name:     "pad",
+			template: `{{range .}}{{pad 10 .Name}}|{{end}}`,
+			data:     apps,
+			expected: "frontend  |api       |",
+		},
+		{
+			name:     "maps",
+			template: `{{.name}}`,
+			data:     map[string]string{"name": "value"},
+			expected: "value",
+		},
+		{
+			name:     "custom funcs",
+			template: `{{shout .}}`,
+			opts: []output.TemplateOptionFunc{
+				output.TemplateWithFuncs(template.FuncMap{"shout": func(s string) string { return s + "!" }}),
+			},
+			data:     "hello",
+			expected: "hello!",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := output.NewTemplate(&buf, tt.template, tt.opts...).Render(tt.data); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if actual := buf.String(); actual != tt.expected {
+				t.Fatalf("expected %q, got: %q", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestTemplate_Errors(t *testing.T) {
+	tests := []struct {
+		name          string
+		template      string
+		errorContains string
+	}{
+		{name: "parse error", template: "{{.Name", errorContains: "unclosed action"},
+		{name: "unknown function", template: "{{nope .}}", errorContains: `function "nope" not defined`},
+		{name: "execution error", template: "{{.Missing}}", errorContains: "can't evaluate field Missing"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := output.NewTemplate(&buf, tt.template).Render(struct{ Name string }{Name: "foo"})
+
+			te, ok := errors.AsType[*output.TemplateError](err)
+			if !ok {
+				t.Fatalf("expected *output.TemplateError, got: %v", err)
+			}
+
+			if !strings.Contains(te.Err.Error(), tt.errorContains) {
+				t.Fatalf("expected error to contain %q, got: %v", tt.errorContains, te.Err)
+			}
+
+			if buf.Len() > 0 {
+				t.Fatalf("expected no output, got: %q", buf.String())
+			}
+		})
+	}
+}
+
+func TestTemplate_Validate(t *testing.T) {
+	tests := []struct {
+		name          string
+		template      string
+		errorContains string
+	}{
+		{name: "valid template", template: "{{.Name | upper}}"},
+		{name: "execution errors are not detected", template: "{{.Missing}}"},
+		{name: "parse error", template: "{{.Name", errorContains: "unclosed action"},
+		{name: "unknown function", template: "{{nope .}}", errorContains: `function "nope" not defined`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := output.NewTemplate(io.Discard, tt.template).Validate()
+			if tt.errorContains == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			te, ok := errors.AsType[*output.TemplateError](err)
+			if !ok {
+				t.Fatalf("expected *output.TemplateError, got: %v", err)
+			}
+
+			if !strings.Contains(te.Err.Error(), tt.errorContains) {
+				t.Fatalf("expected error to contain %q, got: %v", tt.errorContains, te.Err)
+			}
+		})
+	}
+}
@@ -0,0 +1,230 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/nais/naistrix/internal/color"
+	"github.com/pterm/pterm"
+)
+
+// TreeNode is implemented by values that are rendered as a node with child nodes by the [Tree] renderer. The label of
+// the node is the string representation of the value, which can contain inline color tags, like <info> and <error>.
+type TreeNode interface {
+	fmt.Stringer
+
+	// Children returns the child nodes of the node. The child nodes can be any value supported by [Tree.Render].
+	Children() []any
+}
+
+// Box-drawing prefixes used for the branches of the tree when styling is enabled.
+const (
+	treeBranch     = "├── "
+	treeLastBranch = "└── "
+	treeLine       = "│   "
+	treeSpace      = "    "
+)
+
+// treeIndent is the indentation of each level of the tree when styling is disabled.
+const treeIndent = "  "
+
+// Tree is a renderer that writes hierarchical data to an [io.Writer], for instance teams with their applications and
+// the instances of each application. Use [NewTree] to construct one.
+type Tree struct {
+	writer io.Writer
+}
+
+// NewTree creates a new [Tree] renderer that will write to the provided [io.Writer].
+func NewTree(w io.Writer) *Tree {
+	return &Tree{
+		writer: w,
+	}
+}
+
+// treeNode is a node in the tree, with the label and child nodes extracted from the rendered value.
+type treeNode struct {
+	label    string
+	children []treeNode
+
+	// tags is set when the label is returned by a [TreeNode], and the color tags in the label are colorized.
+	tags bool
+}
+
+// Render renders v as a tree. If v is a slice, each element is rendered as a separate tree.
+//
+// Values implementing [TreeNode] are rendered using the string representation of the value as the label, and the
+// values returned by [TreeNode.Children] as child nodes. Structs can be used as nodes by adding a `tree:"label"` field
+// tag to the field holding the label, and a `tree:"children"` field tag to the slice field holding the child nodes.
+// All other values are rendered as leaf nodes, using the string representation of the value as the label. A pointer to
+// a node that is its own ancestor is rendered with a " (cycle)" marker after the label, and without child nodes.
+//
+// The labels of [TreeNode] values can contain inline color tags, while text that looks like the tags in other labels is
+// rendered as is. The branches of the tree are drawn using box-drawing characters. When styling is disabled, the tags
+// are removed, and the child nodes are indented using spaces instead.
+func (t *Tree) Render(v any) error {
+	roots, err := treeNodes(v)
+	if err != nil {
+		return err
+	}
+
+	var sb strings.Builder
+	for _, root := range roots {
+		writeTreeNode(&sb, root, "", "")
+	}
+
+	_, err = io.WriteString(t.writer, sb.String())
+	return err
+}
+
+// treeNodes extracts the root nodes of the tree from v.
+func treeNodes(v any) ([]treeNode, error) {
+	rv := indirect(reflect.ValueOf(v))
+	if !rv.IsValid() {
+		return nil, nil
+	}
+
+	ancestors := make(map[treePointer]bool)
+	if _, ok := v.(TreeNode); !ok && (rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array) {
+		return extractTreeNodes(rv, ancestors)
+	}
+
+	node, err := extractTreeNode(v, ancestors)
+	if err != nil {
+		return nil, err
+	}
+
+	return []treeNode{node}, nil
+}
+
+// extractTreeNodes extracts a node from each element of the slice rv. See [extractTreeNode] for ancestors.
+func extractTreeNodes(rv reflect.Value, ancestors map[treePointer]bool) ([]treeNode, error) {
+	nodes := make([]treeNode, 0, rv.Len())
+	for i := range rv.Len() {
+		node, err := extractTreeNode(rv.Index(i).Interface(), ancestors)
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes, nil
+}
+
+// extractTreeNode extracts the label and child nodes from v. The ancestors are the pointers to the nodes on the path
+// from the root to v. A pointer to one of the ancestors would make the tree infinite, so such a node is labeled with
+// treeCycleMarker, and its child nodes are left out.
+func extractTreeNode(v any, ancestors map[treePointer]bool) (treeNode, error) {
+	cycle := false
+	if p, ok := treeNodePointer(v); ok {
+		if cycle = ancestors[p]; !cycle {
+			ancestors[p] = true
+			defer delete(ancestors, p)
+		}
+	}
+
+	if n, ok := v.(TreeNode); ok {
+		node := treeNode{label: n.String(), tags: true}
+		if cycle {
+			node.label += treeCycleMarker
+			return node, nil
+		}
+
+		for _, child := range n.Children() {
+			c, err := extractTreeNode(child, ancestors)
+			if err != nil {
+				return treeNode{}, err
+			}
+			node.children = append(node.children, c)
+		}
+		return node, nil
+	}
+
+	node := treeNode{}
+	if v != nil {
+		node.label = fmt.Sprint(v)
+	}
+
+	rv := indirect(reflect.ValueOf(v))
+	if !rv.IsValid() || rv.Kind() != reflect.Struct {
+		return node, nil
+	}
+
+	for i := range rv.NumField() {
+		field := rv.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		switch field.Tag.Get("tree") {
+		case "label":
+			node.label = getStringValue(rv.Field(i))
+		case "children":
+			f := indirect(rv.Field(i))
+			if !f.IsValid() || cycle {
+				continue
+			}
+
+			if f.Kind() != reflect.Slice && f.Kind() != reflect.Array {
+				return treeNode{}, fmt.Errorf("field %q with tree:\"children\" tag must be a slice, got %s", field.Name, f.Kind())
+			}
+
+			children, err := extractTreeNodes(f, ancestors)
+			if err != nil {
+				return treeNode{}, err
+			}
+			node.children = children
+		}
+	}
+
+	if cycle {
+		node.label += treeCycleMarker
+	}
+
+	return node, nil
+}
+
+// treeCycleMarker is appended to the labels of nodes that are their own ancestors.
+const treeCycleMarker = " (cycle)"
+
+// treePointer identifies a node referenced by a pointer. The type is included, since a struct and its first field have
+// the same address.
+type treePointer struct {
+	typ reflect.Type
+	ptr uintptr
+}
+
+// treeNodePointer returns the pointer identifying v, if v is a non-nil pointer.
+func treeNodePointer(v any) (treePointer, bool) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return treePointer{}, false
+	}
+	return treePointer{typ: rv.Type(), ptr: rv.Pointer()}, true
+}
+
+// writeTreeNode writes the node and its child nodes to sb. The prefix is written in front of the label of the node,
+// and childPrefix in front of the lines of the child nodes.
+func writeTreeNode(sb *strings.Builder, node treeNode, prefix, childPrefix string) {
+	sb.WriteString(prefix)
+	switch {
+	case !node.tags:
+		sb.WriteString(node.label)
+	case pterm.RawOutput:
+		sb.WriteString(color.Strip(node.label))
+	default:
+		sb.WriteString(color.Colorize(node.label))
+	}
+	sb.WriteString("\n")
+
+	for i, child := range node.children {
+		switch {
+		case pterm.RawOutput:
+			writeTreeNode(sb, child, childPrefix+treeIndent, childPrefix+treeIndent)
+		case i == len(node.children)-1:
+			writeTreeNode(sb, child, childPrefix+treeLastBranch, childPrefix+treeSpace)
+		default:
+			writeTreeNode(sb, child, childPrefix+treeBranch, childPrefix+treeLine)
+		}
+	}
+}
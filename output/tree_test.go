@@ -0,0 +1,183 @@
+package output_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/nais/naistrix/output"
+	"github.com/pterm/pterm"
+)
+
+type treeTeam struct {
+	Slug string     `tree:"label"`
+	Apps []*treeApp `tree:"children"`
+}
+
+type treeApp struct {
+	Name      string   `tree:"label"`
+	Instances []string `tree:"children"`
+}
+
+type treeDir struct {
+	name  string
+	files []any
+}
+
+func (d treeDir) String() string  { return d.name + "/" }
+func (d treeDir) Children() []any { return d.files }
+
+type treeLink struct {
+	name  string
+	links []any
+}
+
+func (l *treeLink) String() string  { return l.name }
+func (l *treeLink) Children() []any { return l.links }
+
+type treeGroup struct {
+	Name   string       `tree:"label"`
+	Groups []*treeGroup `tree:"children"`
+}
+
+func TestTree(t *testing.T) {
+	teams := []treeTeam{
+		{
+			Slug: "team-a",
+			Apps: []*treeApp{
+				{Name: "api", Instances: []string{"api-1", "<error>api-2</error>"}},
+				{Name: "frontend", Instances: []string{"frontend-1"}},
+			},
+		},
+		{Slug: "team-b"},
+	}
+
+	dir := treeDir{
+		name: "root",
+		files: []any{
+			treeDir{name: "<error>src</error>", files: []any{"main.go"}},
+			"go.mod",
+		},
+	}
+
+	shared := &treeLink{name: "shared"}
+	link := &treeLink{name: "a"}
+	link.links = []any{&treeLink{name: "b", links: []any{link, shared}}, shared}
+
+	group := &treeGroup{Name: "admins"}
+	group.Groups = []*treeGroup{{Name: "owners", Groups: []*treeGroup{group}}}
+
+	tests := []struct {
+		name     string
+		data     any
+		styled   string
+		unstyled string
+	}{
+		{
+			name: "struct tags",
+			data: teams,
+			styled: "team-a\n" +
+				"├── api\n" +
+				"│   ├── api-1\n" +
+				"│   └── <error>api-2</error>\n" +
+				"└── frontend\n" +
+				"    └── frontend-1\n" +
+				"team-b\n",
+			unstyled: "team-a\n" +
+				"  api\n" +
+				"    api-1\n" +
+				"    <error>api-2</error>\n" +
+				"  frontend\n" +
+				"    frontend-1\n" +
+				"team-b\n",
+		},
+		{
+			name: "tree node interface",
+			data: dir,
+			styled: "root/\n" +
+				"├── \x1b[91msrc\x1b[0m/\n" +
+				"│   └── main.go\n" +
+				"└── go.mod\n",
+			unstyled: "root/\n" +
+				"  src/\n" +
+				"    main.go\n" +
+				"  go.mod\n",
+		},
+		{
+			name: "tree node cycle",
+			data: link,
+			styled: "a\n" +
+				"├── b\n" +
+				"│   ├── a (cycle)\n" +
+				"│   └── shared\n" +
+				"└── shared\n",
+			unstyled: "a\n" +
+				"  b\n" +
+				"    a (cycle)\n" +
+				"    shared\n" +
+				"  shared\n",
+		},
+		{
+			name: "struct cycle",
+			data: group,
+			styled: "admins\n" +
+				"└── owners\n" +
+				"    └── admins (cycle)\n",
+			unstyled: "admins\n" +
+				"  owners\n" +
+				"    admins (cycle)\n",
+		},
+		{
+			name:     "leaf",
+			data:     "single",
+			styled:   "single\n",
+			unstyled: "single\n",
+		},
+		{
+			name:     "nil",
+			data:     nil,
+			styled:   "",
+			unstyled: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := output.NewTree(&buf).Render(tt.data); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if actual := buf.String(); actual != tt.styled {
+				t.Errorf("expected styled output:\n%s\ngot:\n%s", tt.styled, actual)
+			}
+
+			pterm.DisableStyling()
+			defer pterm.EnableStyling()
+
+			buf.Reset()
+			if err := output.NewTree(&buf).Render(tt.data); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if actual := buf.String(); actual != tt.unstyled {
+				t.Errorf("expected unstyled output:\n%s\ngot:\n%s", tt.unstyled, actual)
+			}
+		})
+	}
+}
+
+func TestTree_InvalidChildren(t *testing.T) {
+	type node struct {
+		Name     string `tree:"label"`
+		Children string `tree:"children"`
+	}
+
+	var buf bytes.Buffer
+	if err := output.NewTree(&buf).Render(node{Name: "a", Children: "b"}); err == nil {
+		t.Fatal("expected error")
+	}
+
+	if buf.Len() > 0 {
+		t.Errorf("expected no output, got %q", buf.String())
+	}
+}
@@ -6,19 +6,82 @@ import (
 	"gopkg.in/yaml.v3"
 )
 
+// YAMLOptionFunc is a function that can be used to configure the [YAML] renderer.
+type YAMLOptionFunc func(*YAML)
+
+// YAMLWithQuery can be used to render only the parts of the value selected by a query expression. See [Query] for the
+// supported syntax. The field names used in the query are the YAML field names. Queries that can select more than one
+// value, for instance by using wildcards, render a list of all selected values. An empty expression renders the whole
+// value.
+func YAMLWithQuery(expression string) YAMLOptionFunc {
+	return func(y *YAML) {
+		y.query = expression
+	}
+}
+
 // YAML is a renderer that encodes values as YAML and writes them to an [io.Writer]. Use [NewYAML] to construct one.
 type YAML struct {
+	query  string
 	writer io.Writer
 }
 
-// NewYAML creates a new [YAML] renderer that will write to the provided [io.Writer].
-func NewYAML(w io.Writer) *YAML {
-	return &YAML{
+// NewYAML creates a new [YAML] renderer that will write to the provided [io.Writer]. The renderer can be configured
+// using the optional [YAMLOptionFunc] arguments.
+func NewYAML(w io.Writer, opts ...YAMLOptionFunc) *YAML {
+	y := &YAML{
 		writer: w,
 	}
+
+	for _, opt := range opts {
+		opt(y)
+	}
+
+	return y
+}
+
+// Validate checks that the query expression set with [YAMLWithQuery] is valid, and returns a [*QueryError] if not. This
+// method satisfies the [Validator] interface.
+func (y *YAML) Validate() error {
+	if y.query == "" {
+		return nil
+	}
+
+	_, err := ParseQuery(y.query)
+	return err
 }
 
-// Render encodes v as YAML and writes the result to the configured [io.Writer].
+// Render encodes v as YAML and writes the result to the configured [io.Writer]. If the query expression set with
+// [YAMLWithQuery] is invalid, a [*QueryError] is returned.
 func (y *YAML) Render(v any) error {
+	if y.query != "" {
+		q, err := ParseQuery(y.query)
+		if err != nil {
+			return err
+		}
+
+		doc, err := normalizeYAML(v)
+		if err != nil {
+			return err
+		}
+
+		v = q.result(doc)
+	}
+
 	return yaml.NewEncoder(y.writer).Encode(v)
 }
+
+// normalizeYAML converts v to a value consisting only of maps, slices and scalar values, by encoding it as YAML and
+// decoding it again.
+func normalizeYAML(v any) (any, error) {
+	b, err := yaml.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc any
+	if err := yaml.Unmarshal(b, &doc); err != nil {
+		return nil, err
+	}
+
+	return doc, nil
+}
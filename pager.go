@@ -0,0 +1,139 @@
+package naistrix
+
+import (
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// defaultPager is the pager command used when the PAGER environment variable is not set.
+const defaultPager = "less -FRX"
+
+// terminalSize returns the size of the terminal w writes to, or zero values if w is not a terminal. It is a package
+// variable so tests can override the detection.
+var terminalSize = func(w io.Writer) (width, height int) {
+	f, ok := w.(interface{ Fd() uintptr })
+	if !ok || !term.IsTerminal(int(f.Fd())) { // #nosec G115
+		return 0, 0
+	}
+
+	width, height, err := term.GetSize(int(f.Fd())) // #nosec G115
+	if err != nil {
+		return 0, 0
+	}
+
+	return width, height
+}
+
+// withPager returns a copy of the output writer that shows the output in a pager. The returned function must be called
+// when the command is done writing output, and waits for the end-user to quit the pager. If the pager is disabled, or
+// the output is not written to a terminal, the output writer is returned as is.
+func (w *OutputWriter) withPager() (*OutputWriter, func() error) {
+	noop := func() error { return nil }
+	if !w.pager || *w.noPager {
+		return w, noop
+	}
+
+	if _, height := terminalSize(w.writer); height <= 0 {
+		return w, noop
+	}
+
+	command := strings.TrimSpace(os.Getenv("PAGER"))
+	if command == "" {
+		command = defaultPager
+	}
+
+	pw := &pagingWriter{
+		out:     w.writer,
+		command: command,
+	}
+
+	cp := *w
+	cp.writer = pw
+	return &cp, pw.Close
+}
+
+// pagingWriter starts a pager on the first write, and writes all output to the pager as it is written, so streamed
+// output is shown right away. Like git, the pager command is run using the shell, and the LESS environment variable is
+// set to "FRX" unless already set, which makes less exit right away when the output fits in the terminal.
+type pagingWriter struct {
+	out     io.Writer
+	command string
+
+	cmd   *exec.Cmd
+	stdin io.WriteCloser
+
+	// passthrough is set when the pager could not be started, and output is written directly to out.
+	passthrough bool
+
+	// quit is set when the pager no longer accepts output, typically because the end-user has quit the pager.
+	quit bool
+}
+
+// Write writes p to the pager, and starts the pager if it is not already running. Errors from the pager are ignored,
+// as they are typically caused by the end-user quitting the pager before all output has been written.
+func (p *pagingWriter) Write(b []byte) (int, error) {
+	if p.stdin == nil && !p.passthrough {
+		if err := p.start(); err != nil {
+			p.passthrough = true
+		}
+	}
+
+	switch {
+	case p.quit:
+		return len(b), nil
+	case p.passthrough:
+		return p.out.Write(b)
+	}
+
+	if _, err := p.stdin.Write(b); err != nil {
+		p.quit = true
+	}
+	return len(b), nil
+}
+
+// start starts the pager command.
+func (p *pagingWriter) start() error {
+	cmd := exec.Command("sh", "-c", p.command) // #nosec G204
+	cmd.Stdout = p.out
+	cmd.Stderr = os.Stderr
+	if _, ok := os.LookupEnv("LESS"); !ok {
+		cmd.Env = append(os.Environ(), "LESS=FRX")
+	}
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	p.cmd = cmd
+	p.stdin = stdin
+	return nil
+}
+
+// Close waits for the end-user to quit the pager, if the pager was started.
+func (p *pagingWriter) Close() error {
+	if p.cmd == nil {
+		return nil
+	}
+
+	_ = p.stdin.Close()
+	_ = p.cmd.Wait()
+	return nil
+}
+
+// Fd returns the file descriptor of the destination, which makes it possible to detect the size of the terminal when
+// writing through the pager.
+func (p *pagingWriter) Fd() uintptr {
+	if f, ok := p.out.(interface{ Fd() uintptr }); ok {
+		return f.Fd()
+	}
+	return ^uintptr(0)
+}
@@ -0,0 +1,120 @@
+package naistrix_test
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/nais/naistrix"
+)
+
+// TestFakePager is not a real test, but a fake pager used by TestOutputWriter_Pager. It prefixes the output with a
+// marker and its arguments, so the tests can tell whether the output went through the pager.
+func TestFakePager(t *testing.T) {
+	if os.Getenv("NAISTRIX_FAKE_PAGER") != "1" {
+		t.Skip("only used as a fake pager")
+	}
+
+	b, _ := io.ReadAll(os.Stdin)
+	fmt.Printf("[pager %s]\n%s", strings.Join(flag.Args(), ","), b)
+	os.Exit(0)
+}
+
+func TestOutputWriter_Pager(t *testing.T) {
+	t.Setenv("NAISTRIX_FAKE_PAGER", "1")
+	t.Setenv("PAGER", os.Args[0]+` -test.run='^TestFakePager$' -- "--prompt=x y"`)
+
+	tests := []struct {
+		name     string
+		height   int
+		pager    bool
+		args     []string
+		lines    int
+		expected string
+	}{
+		{
+			name:     "long output",
+			height:   3,
+			pager:    true,
+			lines:    4,
+			expected: "[pager --prompt=x y]\nline 1\nline 2\nline 3\nline 4\n",
+		},
+		{
+			name:     "short output",
+			height:   10,
+			pager:    true,
+			lines:    2,
+			expected: "[pager --prompt=x y]\nline 1\nline 2\n",
+		},
+		{
+			name:   "no output",
+			height: 3,
+			pager:  true,
+		},
+		{
+			name:     "disabled by the end-user",
+			height:   3,
+			pager:    true,
+			args:     []string{"--no-pager"},
+			lines:    4,
+			expected: "line 1\nline 2\nline 3\nline 4\n",
+		},
+		{
+			name:     "not a terminal",
+			pager:    true,
+			lines:    4,
+			expected: "line 1\nline 2\nline 3\nline 4\n",
+		},
+		{
+			name:     "not enabled",
+			height:   3,
+			lines:    4,
+			expected: "line 1\nline 2\nline 3\nline 4\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			restore := naistrix.SetTerminalSize(func(io.Writer) (int, int) { return 80, tt.height })
+			defer restore()
+
+			var buf bytes.Buffer
+			opts := []naistrix.ApplicationOptionFunc{naistrix.ApplicationWithWriter(&buf)}
+			if tt.pager {
+				opts = append(opts, naistrix.ApplicationWithPager())
+			}
+
+			app, _, err := naistrix.NewApplication("app", "title", "v0.0.0", opts...)
+			if err != nil {
+				t.Fatalf("unable to create application: %v", err)
+			}
+
+			err = app.AddCommand(&naistrix.Command{
+				Name:  "test",
+				Title: "Test command",
+				RunFunc: func(_ context.Context, _ *naistrix.Arguments, out *naistrix.OutputWriter) error {
+					for i := range tt.lines {
+						out.Printf("line %d\n", i+1)
+					}
+					return nil
+				},
+			})
+			if err != nil {
+				t.Fatalf("unable to add command: %v", err)
+			}
+
+			if err := app.Run(naistrix.RunWithArgs(append([]string{"test"}, tt.args...))); err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			if actual := buf.String(); actual != tt.expected {
+				t.Errorf("expected output %q, got %q", tt.expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,248 @@
+package naistrix
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/pterm/pterm"
+)
+
+// progressReportSteps is the number of lines written by a [Progress] when the progress can not be animated, one for
+// each step towards completion.
+const progressReportSteps = 10
+
+// indicator is implemented by the progress indicators that are stopped automatically when a command returns.
+type indicator interface {
+	Stop()
+}
+
+// indicators keeps track of the progress indicators started while running a command.
+type indicators struct {
+	mu     sync.Mutex
+	active []indicator
+}
+
+// add registers a started progress indicator.
+func (i *indicators) add(ind indicator) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.active = append(i.active, ind)
+}
+
+// stop stops all registered progress indicators.
+func (i *indicators) stop() {
+	i.mu.Lock()
+	active := i.active
+	i.active = nil
+	i.mu.Unlock()
+
+	for _, ind := range active {
+		ind.Stop()
+	}
+}
+
+// withIndicators returns a copy of the output writer that keeps track of the progress indicators started through it.
+// The indicators are stopped when the context is cancelled, or when the returned function is called.
+func (w *OutputWriter) withIndicators(ctx context.Context) (*OutputWriter, func()) {
+	ind := &indicators{}
+	stopAfter := context.AfterFunc(ctx, ind.stop)
+
+	cp := *w
+	cp.indicators = ind
+	return &cp, func() {
+		stopAfter()
+		ind.stop()
+	}
+}
+
+// animated reports whether progress indicators can be animated, which requires the error writer to be a terminal and
+// styling to be enabled.
+func (w *OutputWriter) animated() bool {
+	width, _ := terminalSize(w.errWriter)
+	return width > 0 && !pterm.RawOutput
+}
+
+// track registers a progress indicator, so it is stopped when the command returns.
+func (w *OutputWriter) track(ind indicator) {
+	if w.indicators != nil {
+		w.indicators.add(ind)
+	}
+}
+
+// Spinner is an indicator for operations of unknown duration. Use [OutputWriter.Spinner] to start one.
+type Spinner struct {
+	mu      sync.Mutex
+	out     *OutputWriter
+	printer *pterm.SpinnerPrinter
+	stopped bool
+}
+
+// Spinner starts a spinner with the provided message, written to the error destination. The spinner is animated when
+// writing to a terminal, otherwise the message is written as a single line. Nothing is written in quiet mode.
+//
+// The spinner is stopped automatically when the RunFunc of the command returns, or when the context of the command is
+// cancelled, but should be stopped using [Spinner.Success], [Spinner.Fail] or [Spinner.Stop] when the operation is
+// done.
+func (w *OutputWriter) Spinner(msg string) *Spinner {
+	s := &Spinner{out: w}
+	w.track(s)
+
+	switch {
+	case *w.quiet:
+	case w.animated():
+		s.printer, _ = pterm.DefaultSpinner.WithWriter(w.errWriter).WithRemoveWhenDone().Start(msg)
+	default:
+		_, _ = fmt.Fprintln(w.errWriter, msg)
+	}
+
+	return s
+}
+
+// UpdateText changes the message of the spinner. When the spinner is not animated, the message is written as a new
+// line.
+func (s *Spinner) UpdateText(msg string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch {
+	case s.stopped || *s.out.quiet:
+	case s.printer != nil:
+		s.printer.UpdateText(msg)
+	default:
+		_, _ = fmt.Fprintln(s.out.errWriter, msg)
+	}
+}
+
+// Success stops the spinner, and writes a "successful" message using [OutputWriter.Successln].
+func (s *Spinner) Success(msg string) {
+	if s.stop() {
+		s.out.Successln(msg)
+	}
+}
+
+// Fail stops the spinner, and writes an error message using [OutputWriter.Errorln].
+func (s *Spinner) Fail(msg string) {
+	if s.stop() {
+		s.out.Errorln(msg)
+	}
+}
+
+// Stop stops the spinner without writing any message. Stopping a spinner that has already been stopped is a no-op.
+func (s *Spinner) Stop() {
+	s.stop()
+}
+
+// stop stops the spinner, and reports whether the spinner was running.
+func (s *Spinner) stop() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.stopped {
+		return false
+	}
+
+	s.stopped = true
+	if s.printer != nil {
+		_ = s.printer.Stop()
+	}
+
+	return true
+}
+
+// Progress is an indicator for operations with a known number of steps. Use [OutputWriter.Progress] to start one.
+type Progress struct {
+	mu       sync.Mutex
+	out      *OutputWriter
+	printer  *pterm.ProgressbarPrinter
+	title    string
+	total    int
+	current  int
+	reported int
+	stopped  bool
+}
+
+// Progress starts a progress bar for an operation with total steps, written to the error destination. The progress bar
+// is animated when writing to a terminal, otherwise a line with the current progress is written for every 10% of
+// completion. Nothing is written in quiet mode.
+//
+// The progress bar is stopped automatically when the RunFunc of the command returns, or when the context of the
+// command is cancelled.
+func (w *OutputWriter) Progress(total int) *Progress {
+	p := &Progress{out: w, title: "Progress", total: max(total, 1)}
+	w.track(p)
+
+	if !*w.quiet && w.animated() {
+		p.printer, _ = pterm.DefaultProgressbar.
+			WithWriter(w.errWriter).
+			WithTotal(p.total).
+			WithRemoveWhenDone().
+			Start(p.title)
+	}
+
+	return p
+}
+
+// UpdateTitle changes the title of the progress bar, which defaults to "Progress".
+func (p *Progress) UpdateTitle(title string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.title = title
+	if p.printer != nil && !p.stopped {
+		p.printer.UpdateTitle(title)
+	}
+}
+
+// Increment advances the progress bar by one step.
+func (p *Progress) Increment() {
+	p.Add(1)
+}
+
+// Add advances the progress bar by n steps. The progress bar is stopped when all steps are done. Since the progress can
+// not go backwards, a non-positive n is ignored.
+func (p *Progress) Add(n int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.stopped || n <= 0 {
+		return
+	}
+
+	n = min(n, p.total-p.current)
+	p.current += n
+
+	switch {
+	case *p.out.quiet:
+	case p.printer != nil:
+		p.printer.Add(n)
+	default:
+		if step := p.current * progressReportSteps / p.total; step > p.reported {
+			p.reported = step
+			_, _ = fmt.Fprintf(p.out.errWriter, "%s: %d/%d (%d%%)\n", p.title, p.current, p.total, p.current*100/p.total)
+		}
+	}
+
+	if p.current >= p.total {
+		p.stopLocked()
+	}
+}
+
+// Stop stops the progress bar. Stopping a progress bar that has already been stopped is a no-op.
+func (p *Progress) Stop() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.stopLocked()
+}
+
+// stopLocked stops the progress bar. The caller must hold the lock.
+func (p *Progress) stopLocked() {
+	if p.stopped {
+		return
+	}
+
+	p.stopped = true
+	if p.printer != nil {
+		_, _ = p.printer.Stop()
+	}
+}
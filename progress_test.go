@@ -0,0 +1,167 @@
+package naistrix_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/nais/naistrix"
+	"github.com/pterm/pterm"
+)
+
+func TestOutputWriter_ProgressIndicators(t *testing.T) {
+	pterm.DisableStyling()
+	defer pterm.EnableStyling()
+
+	tests := []struct {
+		name        string
+		args        []string
+		run         func(out *naistrix.OutputWriter)
+		expectedErr string
+	}{
+		{
+			name: "spinner",
+			run: func(out *naistrix.OutputWriter) {
+				s := out.Spinner("Deploying")
+				s.UpdateText("Waiting for rollout")
+				s.Success("Deployed")
+				s.Fail("ignored, already stopped")
+			},
+			expectedErr: "Deploying\nWaiting for rollout\nSUCCESS: Deployed\n",
+		},
+		{
+			name: "failing spinner",
+			run: func(out *naistrix.OutputWriter) {
+				out.Spinner("Deploying").Fail("Deploy failed")
+			},
+			expectedErr: "Deploying\nERROR: Deploy failed\n",
+		},
+		{
+			name: "progress",
+			run: func(out *naistrix.OutputWriter) {
+				p := out.Progress(20)
+				p.UpdateTitle("Syncing")
+				for range 25 {
+					p.Increment()
+				}
+			},
+			expectedErr: "Syncing: 2/20 (10%)\nSyncing: 4/20 (20%)\nSyncing: 6/20 (30%)\nSyncing: 8/20 (40%)\n" +
+				"Syncing: 10/20 (50%)\nSyncing: 12/20 (60%)\nSyncing: 14/20 (70%)\nSyncing: 16/20 (80%)\n" +
+				"Syncing: 18/20 (90%)\nSyncing: 20/20 (100%)\n",
+		},
+		{
+			name: "progress in large steps",
+			run: func(out *naistrix.OutputWriter) {
+				p := out.Progress(3)
+				p.Add(2)
+				p.Stop()
+				p.Add(1)
+			},
+			expectedErr: "Progress: 2/3 (66%)\n",
+		},
+		{
+			name: "progress with non-positive steps",
+			run: func(out *naistrix.OutputWriter) {
+				p := out.Progress(2)
+				p.Add(-5)
+				p.Add(0)
+				p.Add(1)
+				p.Add(-1)
+				p.Add(1)
+			},
+			expectedErr: "Progress: 1/2 (50%)\nProgress: 2/2 (100%)\n",
+		},
+		{
+			name: "quiet",
+			args: []string{"--quiet"},
+			run: func(out *naistrix.OutputWriter) {
+				out.Spinner("Deploying").Success("Deployed")
+				out.Progress(1).Increment()
+			},
+			expectedErr: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf, errBuf bytes.Buffer
+			app, _, err := naistrix.NewApplication(
+				"app",
+				"title",
+				"v0.0.0",
+				naistrix.ApplicationWithWriter(&buf),
+				naistrix.ApplicationWithErrorWriter(&errBuf),
+			)
+			if err != nil {
+				t.Fatalf("unable to create application: %v", err)
+			}
+
+			err = app.AddCommand(&naistrix.Command{
+				Name:  "test",
+				Title: "Test command",
+				RunFunc: func(_ context.Context, _ *naistrix.Arguments, out *naistrix.OutputWriter) error {
+					tt.run(out)
+					return nil
+				},
+			})
+			if err != nil {
+				t.Fatalf("unable to add command: %v", err)
+			}
+
+			if err := app.Run(naistrix.RunWithArgs(append([]string{"test"}, tt.args...))); err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			if buf.Len() > 0 {
+				t.Errorf("expected no output, got %q", buf.String())
+			}
+
+			if actual := errBuf.String(); actual != tt.expectedErr {
+				t.Errorf("expected error output %q, got %q", tt.expectedErr, actual)
+			}
+		})
+	}
+}
+
+func TestOutputWriter_ProgressIndicatorsStoppedWhenCommandReturns(t *testing.T) {
+	pterm.DisableStyling()
+	defer pterm.EnableStyling()
+
+	var errBuf bytes.Buffer
+	app, _, err := naistrix.NewApplication(
+		"app",
+		"title",
+		"v0.0.0",
+		naistrix.ApplicationWithWriter(&bytes.Buffer{}),
+		naistrix.ApplicationWithErrorWriter(&errBuf),
+	)
+	if err != nil {
+		t.Fatalf("unable to create application: %v", err)
+	}
+
+	var spinner *naistrix.Spinner
+	var progress *naistrix.Progress
+	err = app.AddCommand(&naistrix.Command{
+		Name:  "test",
+		Title: "Test command",
+		RunFunc: func(_ context.Context, _ *naistrix.Arguments, out *naistrix.OutputWriter) error {
+			spinner = out.Spinner("Working")
+			progress = out.Progress(1)
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("unable to add command: %v", err)
+	}
+
+	if err := app.Run(naistrix.RunWithArgs([]string{"test"})); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	spinner.Success("too late")
+	progress.Increment()
+
+	if expected, actual := "Working\n", errBuf.String(); actual != expected {
+		t.Errorf("expected error output %q, got %q", expected, actual)
+	}
+}
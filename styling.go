@@ -0,0 +1,91 @@
+package naistrix
+
+import (
+	"context"
+	"io"
+	"os"
+	"slices"
+
+	"github.com/nais/naistrix/internal/color"
+	"github.com/pterm/pterm"
+)
+
+// ColorMode controls when colors and other styling are used in the output, and is set by the end-user using the global
+// --color flag.
+type ColorMode string
+
+const (
+	// ColorModeAuto uses colors when the output is written to a terminal, unless disabled by the environment. This is
+	// the default.
+	ColorModeAuto ColorMode = "auto"
+	// ColorModeAlways always uses colors, for instance in CI systems that support colors.
+	ColorModeAlways ColorMode = "always"
+	// ColorModeNever never uses colors.
+	ColorModeNever ColorMode = "never"
+)
+
+// colorModes are the valid values of the --color flag.
+var colorModes = []string{string(ColorModeAuto), string(ColorModeAlways), string(ColorModeNever)}
+
+// AutoComplete suggests the valid values of the --color flag.
+func (ColorMode) AutoComplete(context.Context, *Arguments, string, any) ([]string, string) {
+	return colorModes, "Available color modes"
+}
+
+// valid reports whether m is one of the valid color modes.
+func (m ColorMode) valid() bool {
+	return slices.Contains(colorModes, string(m))
+}
+
+// useColors reports whether colors should be used when writing to w. Unless colors are turned on or off by the mode,
+// the following conventions are followed, in order:
+//
+//   - colors are disabled when the NO_COLOR environment variable is set to a non-empty value
+//   - colors are enabled when the CLICOLOR_FORCE environment variable is set to a value other than "0"
+//   - colors are disabled when the CLICOLOR environment variable is set to "0"
+//   - colors are disabled when the TERM environment variable is set to "dumb"
+//   - colors are enabled when w is a terminal
+//
+// The second return value reports whether colors are forced, in which case colors are used even if the terminal does
+// not appear to support colors.
+func useColors(mode ColorMode, w io.Writer) (enabled, forced bool) {
+	switch mode {
+	case ColorModeAlways:
+		return true, true
+	case ColorModeNever:
+		return false, false
+	}
+
+	if os.Getenv("NO_COLOR") != "" {
+		return false, false
+	}
+
+	if v := os.Getenv("CLICOLOR_FORCE"); v != "" && v != "0" {
+		return true, true
+	}
+
+	if os.Getenv("CLICOLOR") == "0" || os.Getenv("TERM") == "dumb" {
+		return false, false
+	}
+
+	width, _ := terminalSize(w)
+	return width > 0, false
+}
+
+// setupStyling enables or disables styling of the output written to the destination of the application, based on the
+// global --color flag and the environment. Colors forced by an earlier run of the application are reset.
+func (a *Application) setupStyling() {
+	if a.flags.NoColors {
+		a.flags.Color = ColorModeNever
+	}
+	a.flags.NoColors = a.flags.Color == ColorModeNever
+
+	enabled, forced := useColors(a.flags.Color, a.writer)
+	color.SetForceColors(forced)
+	if !enabled {
+		pterm.DisableStyling()
+		return
+	}
+
+	pterm.EnableStyling()
+}
@@ -0,0 +1,215 @@
+package naistrix_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nais/naistrix"
+	"github.com/pterm/pterm"
+)
+
+func TestUseColors(t *testing.T) {
+	tests := []struct {
+		name     string
+		mode     naistrix.ColorMode
+		env      map[string]string
+		terminal bool
+		enabled  bool
+		forced   bool
+	}{
+		{name: "terminal", mode: naistrix.ColorModeAuto, terminal: true, enabled: true},
+		{name: "not a terminal", mode: naistrix.ColorModeAuto},
+		{name: "always", mode: naistrix.ColorModeAlways, env: map[string]string{"NO_COLOR": "1"}, enabled: true, forced: true},
+		{name: "never", mode: naistrix.ColorModeNever, terminal: true},
+		{name: "NO_COLOR", mode: naistrix.ColorModeAuto, env: map[string]string{"NO_COLOR": "1"}, terminal: true},
+		{
+			name:    "NO_COLOR takes precedence over CLICOLOR_FORCE",
+			mode:    naistrix.ColorModeAuto,
+			env:     map[string]string{"NO_COLOR": "1", "CLICOLOR_FORCE": "1"},
+			enabled: false,
+		},
+		{name: "CLICOLOR_FORCE", mode: naistrix.ColorModeAuto, env: map[string]string{"CLICOLOR_FORCE": "1"}, enabled: true, forced: true},
+		{name: "CLICOLOR_FORCE=0", mode: naistrix.ColorModeAuto, env: map[string]string{"CLICOLOR_FORCE": "0"}, terminal: true, enabled: true},
+		{name: "CLICOLOR=0", mode: naistrix.ColorModeAuto, env: map[string]string{"CLICOLOR": "0"}, terminal: true},
+		{name: "TERM=dumb", mode: naistrix.ColorModeAuto, env: map[string]string{"TERM": "dumb"}, terminal: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, key := range []string{"NO_COLOR", "CLICOLOR_FORCE", "CLICOLOR", "TERM"} {
+				t.Setenv(key, tt.env[key])
+			}
+
+			restore := naistrix.SetTerminalSize(func(io.Writer) (int, int) {
+				if tt.terminal {
+					return 80, 24
+				}
+				return 0, 0
+			})
+			defer restore()
+
+			enabled, forced := naistrix.UseColors(tt.mode, &bytes.Buffer{})
+			if enabled != tt.enabled || forced != tt.forced {
+				t.Errorf("expected enabled=%v forced=%v, got enabled=%v forced=%v", tt.enabled, tt.forced, enabled, forced)
+			}
+		})
+	}
+}
+
+func TestColorFlag(t *testing.T) {
+	defer pterm.EnableStyling()
+
+	run := func(args ...string) (string, error) {
+		var buf bytes.Buffer
+		app, _, err := naistrix.NewApplication(
+			"app",
+			"title",
+			"v0.0.0",
+			naistrix.ApplicationWithWriter(&buf),
+			naistrix.ApplicationWithErrorWriter(&bytes.Buffer{}),
+		)
+		if err != nil {
+			t.Fatalf("unable to create application: %v", err)
+		}
+
+		err = app.AddCommand(&naistrix.Command{
+			Name:  "test",
+			Title: "Test command",
+			RunFunc: func(_ context.Context, _ *naistrix.Arguments, out *naistrix.OutputWriter) error {
+				out.Println("<info>colored</info>")
+				return nil
+			},
+		})
+		if err != nil {
+			t.Fatalf("unable to add command: %v", err)
+		}
+
+		err = app.Run(naistrix.RunWithArgs(append([]string{"test"}, args...)))
+		return buf.String(), err
+	}
+
+	if out, err := run("--color=always"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	} else if expected := "\x1b[96mcolored\x1b[0m\n"; out != expected {
+		t.Errorf("expected output %q, got %q", expected, out)
+	}
+
+	for _, args := range [][]string{{"--color=never"}, {"--color=auto"}, {"--no-colors"}} {
+		if out, err := run(args...); err != nil {
+			t.Fatalf("%v: expected no error, got %v", args, err)
+		} else if expected := "colored\n"; out != expected {
+			t.Errorf("%v: expected output %q, got %q", args, expected, out)
+		}
+	}
+
+	if _, err := run("--color=sometimes"); err == nil || !strings.Contains(err.Error(), `Invalid color mode "sometimes"`) {
+		t.Errorf("expected invalid color mode error, got %v", err)
+	}
+}
+
+func TestColorFlag_NoColors(t *testing.T) {
+	defer pterm.EnableStyling()
+
+	tests := []struct {
+		args     []string
+		color    naistrix.ColorMode
+		noColors bool
+	}{
+		{args: []string{"--color=always"}, color: naistrix.ColorModeAlways},
+		{args: []string{"--color=never"}, color: naistrix.ColorModeNever, noColors: true},
+		{args: []string{"--no-colors"}, color: naistrix.ColorModeNever, noColors: true},
+	}
+
+	for _, tt := range tests {
+		app, flags, err := naistrix.NewApplication(
+			"app",
+			"title",
+			"v0.0.0",
+			naistrix.ApplicationWithWriter(&bytes.Buffer{}),
+			naistrix.ApplicationWithErrorWriter(&bytes.Buffer{}),
+		)
+		if err != nil {
+			t.Fatalf("unable to create application: %v", err)
+		}
+
+		err = app.AddCommand(&naistrix.Command{
+			Name:  "test",
+			Title: "Test command",
+			RunFunc: func(context.Context, *naistrix.Arguments, *naistrix.OutputWriter) error {
+				return nil
+			},
+		})
+		if err != nil {
+			t.Fatalf("unable to add command: %v", err)
+		}
+
+		if err := app.Run(naistrix.RunWithArgs(append([]string{"test"}, tt.args...))); err != nil {
+			t.Fatalf("%v: expected no error, got %v", tt.args, err)
+		}
+
+		//lint:ignore SA1019 the deprecated field must still be set
+		noColors := flags.NoColors
+		if flags.Color != tt.color || noColors != tt.noColors {
+			t.Errorf(
+				"%v: expected color=%s noColors=%v, got color=%s noColors=%v",
+				tt.args, tt.color, tt.noColors, flags.Color, noColors,
+			)
+		}
+	}
+}
+
+func TestColorFlag_NoColorsDeprecationWarning(t *testing.T) {
+	defer pterm.EnableStyling()
+
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(configPath, []byte("no-colors: true\n"), 0o600); err != nil {
+		t.Fatalf("unable to write configuration file: %v", err)
+	}
+
+	tests := []struct {
+		args    []string
+		warning bool
+	}{
+		{args: []string{"--no-colors"}, warning: true},
+		{args: []string{"--no-colors", "--quiet"}},
+		{args: []string{"--config", configPath}},
+	}
+
+	for _, tt := range tests {
+		errBuf := &bytes.Buffer{}
+		app, _, err := naistrix.NewApplication(
+			"app",
+			"title",
+			"v0.0.0",
+			naistrix.ApplicationWithWriter(&bytes.Buffer{}),
+			naistrix.ApplicationWithErrorWriter(errBuf),
+		)
+		if err != nil {
+			t.Fatalf("unable to create application: %v", err)
+		}
+
+		err = app.AddCommand(&naistrix.Command{
+			Name:  "test",
+			Title: "Test command",
+			RunFunc: func(context.Context, *naistrix.Arguments, *naistrix.OutputWriter) error {
+				return nil
+			},
+		})
+		if err != nil {
+			t.Fatalf("unable to add command: %v", err)
+		}
+
+		if err := app.Run(naistrix.RunWithArgs(append([]string{"test"}, tt.args...))); err != nil {
+			t.Fatalf("%v: expected no error, got %v", tt.args, err)
+		}
+
+		if warning := strings.Contains(errBuf.String(), "--no-colors flag is deprecated"); warning != tt.warning {
+			t.Errorf("%v: expected warning to be %v, got output %q", tt.args, tt.warning, errBuf.String())
+		}
+	}
+}
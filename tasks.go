@@ -0,0 +1,198 @@
+package naistrix
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/pterm/pterm"
+)
+
+// taskStatus is the status of a task in a [Tasks] group.
+type taskStatus string
+
+const (
+	taskPending taskStatus = "pending"
+	taskRunning taskStatus = "running"
+	taskDone    taskStatus = "done"
+	taskFailed  taskStatus = "failed"
+)
+
+// TaskFunc is the function executed by a task in a [Tasks] group. The context is cancelled when the context passed to
+// [Tasks.Run] is cancelled.
+type TaskFunc func(ctx context.Context) error
+
+// TasksOptionFunc is a function that can be used to configure a [Tasks] group.
+type TasksOptionFunc func(*Tasks)
+
+// TasksWithConcurrency limits the number of tasks that run at the same time. The default is to run all tasks at the
+// same time.
+func TasksWithConcurrency(n int) TasksOptionFunc {
+	return func(t *Tasks) {
+		t.concurrency = n
+	}
+}
+
+// task is a single named task in a [Tasks] group.
+type task struct {
+	name   string
+	fn     TaskFunc
+	status taskStatus
+	err    error
+}
+
+// Tasks is a group of named tasks that run concurrently, with a status line for each task. Use [OutputWriter.Tasks] to
+// create one.
+type Tasks struct {
+	out         *OutputWriter
+	concurrency int
+
+	mu    sync.Mutex
+	tasks []*task
+	area  *taskArea
+}
+
+// Tasks creates a new group of tasks that write their status to the error destination. Add tasks to the group using
+// [Tasks.Add], and run them using [Tasks.Run]. The group can be configured using the optional [TasksOptionFunc]
+// arguments.
+func (w *OutputWriter) Tasks(opts ...TasksOptionFunc) *Tasks {
+	t := &Tasks{out: w}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+// Add adds a named task to the group. Tasks must be added before the group is run.
+func (t *Tasks) Add(name string, fn TaskFunc) *Tasks {
+	t.tasks = append(t.tasks, &task{name: name, fn: fn, status: taskPending})
+	return t
+}
+
+// Run runs all tasks in the group concurrently, and waits for them to finish. Each task is shown with a status line
+// that is updated as the task goes from pending, to running, and then to done or failed. When writing to a terminal the
+// status lines are updated in place, otherwise a line is written for every change of status. A summary is written when
+// all tasks have finished. Nothing is written in quiet mode, where the errors of failed tasks are only returned.
+//
+// The returned error joins the errors of all failed tasks, each prefixed with the name of the task.
+func (t *Tasks) Run(ctx context.Context) error {
+	if t.out.animated() && !t.out.IsQuiet() {
+		t.area = &taskArea{writer: t.out.errWriter}
+		t.area.update(t.render())
+	}
+
+	workers := t.concurrency
+	if workers <= 0 || workers > len(t.tasks) {
+		workers = len(t.tasks)
+	}
+
+	// tasks are started in the order they were added, by a fixed number of workers
+	queue := make(chan *task, len(t.tasks))
+	for _, tsk := range t.tasks {
+		queue <- tsk
+	}
+	close(queue)
+
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Go(func() {
+			for tsk := range queue {
+				t.run(ctx, tsk)
+			}
+		})
+	}
+	wg.Wait()
+
+	errs := make([]error, 0)
+	failed := 0
+	for _, tsk := range t.tasks {
+		if tsk.err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", tsk.name, tsk.err))
+			failed++
+		}
+	}
+
+	if !t.out.IsQuiet() {
+		_, _ = fmt.Fprintf(t.out.errWriter, "%d tasks: %d done, %d failed\n", len(t.tasks), len(t.tasks)-failed, failed)
+	}
+
+	return errors.Join(errs...)
+}
+
+// run runs a single task. Tasks that have not been started when the context is cancelled fail without running.
+func (t *Tasks) run(ctx context.Context, tsk *task) {
+	if err := ctx.Err(); err != nil {
+		t.update(tsk, taskFailed, err)
+		return
+	}
+
+	t.update(tsk, taskRunning, nil)
+	if err := tsk.fn(ctx); err != nil {
+		t.update(tsk, taskFailed, err)
+		return
+	}
+	t.update(tsk, taskDone, nil)
+}
+
+// update changes the status of a task, and writes the new status.
+func (t *Tasks) update(tsk *task, status taskStatus, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	tsk.status = status
+	tsk.err = err
+
+	switch {
+	case t.out.IsQuiet():
+	case t.area != nil:
+		t.area.update(t.render())
+	default:
+		_, _ = fmt.Fprintln(t.out.errWriter, taskLine(tsk))
+	}
+}
+
+// render returns the status lines of all tasks in the group.
+func (t *Tasks) render() string {
+	lines := make([]string, len(t.tasks))
+	for i, tsk := range t.tasks {
+		lines[i] = taskStyles[tsk.status].Sprint(taskLine(tsk))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// taskArea writes the status lines of the tasks in a group to a terminal, and rewrites them in place when they change.
+// The area printer of pterm is not used, as it always writes to stdout.
+type taskArea struct {
+	writer io.Writer
+	lines  int
+}
+
+// update replaces the previously written status lines with s.
+func (a *taskArea) update(s string) {
+	if a.lines > 0 {
+		// move the cursor to the start of the first status line, and clear everything below it
+		_, _ = fmt.Fprintf(a.writer, "\x1b[%dF\x1b[J", a.lines)
+	}
+
+	_, _ = fmt.Fprintln(a.writer, s)
+	a.lines = strings.Count(s, "\n") + 1
+}
+
+// taskStyles are the styles used for the status lines of tasks when writing to a terminal.
+var taskStyles = map[taskStatus]*pterm.Style{
+	taskPending: pterm.NewStyle(pterm.FgGray),
+	taskRunning: pterm.NewStyle(pterm.FgCyan),
+	taskDone:    pterm.NewStyle(pterm.FgGreen),
+	taskFailed:  pterm.NewStyle(pterm.FgRed),
+}
+
+// taskLine returns the status line of a task.
+func taskLine(tsk *task) string {
+	if tsk.err != nil {
+		return fmt.Sprintf("%s: %s: %v", tsk.name, tsk.status, tsk.err)
+	}
+	return fmt.Sprintf("%s: %s", tsk.name, tsk.status)
+}
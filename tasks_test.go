@@ -0,0 +1,278 @@
+package naistrix_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/nais/naistrix"
+	"github.com/pterm/pterm"
+)
+
+func TestOutputWriter_Tasks(t *testing.T) {
+	pterm.DisableStyling()
+	defer pterm.EnableStyling()
+
+	ok := func(context.Context) error { return nil }
+	fail := func(context.Context) error { return errors.New("connection refused") }
+
+	tests := []struct {
+		name           string
+		args           []string
+		terminal       bool
+		tasks          map[string]naistrix.TaskFunc
+		order          []string
+		expectedErr    string
+		expectedOutput string
+	}{
+		{
+			name:  "all tasks done",
+			tasks: map[string]naistrix.TaskFunc{"cluster-a": ok, "cluster-b": ok},
+			order: []string{"cluster-a", "cluster-b"},
+			expectedOutput: "cluster-a: running\ncluster-a: done\ncluster-b: running\ncluster-b: done\n" +
+				"2 tasks: 2 done, 0 failed\n",
+		},
+		{
+			name:        "failing tasks",
+			tasks:       map[string]naistrix.TaskFunc{"cluster-a": fail, "cluster-b": ok, "cluster-c": fail},
+			order:       []string{"cluster-a", "cluster-b", "cluster-c"},
+			expectedErr: "cluster-a: connection refused\ncluster-c: connection refused",
+			expectedOutput: "cluster-a: running\ncluster-a: failed: connection refused\n" +
+				"cluster-b: running\ncluster-b: done\n" +
+				"cluster-c: running\ncluster-c: failed: connection refused\n" +
+				"3 tasks: 1 done, 2 failed\n",
+		},
+		{
+			name:        "quiet",
+			args:        []string{"--quiet"},
+			tasks:       map[string]naistrix.TaskFunc{"cluster-a": fail, "cluster-b": ok},
+			order:       []string{"cluster-a", "cluster-b"},
+			expectedErr: "cluster-a: connection refused",
+		},
+		{
+			name:        "quiet in a terminal",
+			args:        []string{"--quiet"},
+			terminal:    true,
+			tasks:       map[string]naistrix.TaskFunc{"cluster-a": fail, "cluster-b": ok},
+			order:       []string{"cluster-a", "cluster-b"},
+			expectedErr: "cluster-a: connection refused",
+		},
+		{
+			name:           "no tasks",
+			expectedOutput: "0 tasks: 0 done, 0 failed\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.terminal {
+				restore := naistrix.SetTerminalSize(func(io.Writer) (int, int) { return 80, 24 })
+				defer restore()
+			}
+
+			var errBuf bytes.Buffer
+			app, _, err := naistrix.NewApplication(
+				"app",
+				"title",
+				"v0.0.0",
+				naistrix.ApplicationWithWriter(&bytes.Buffer{}),
+				naistrix.ApplicationWithErrorWriter(&errBuf),
+			)
+			if err != nil {
+				t.Fatalf("unable to create application: %v", err)
+			}
+
+			var runErr error
+			err = app.AddCommand(&naistrix.Command{
+				Name:  "test",
+				Title: "Test command",
+				RunFunc: func(ctx context.Context, _ *naistrix.Arguments, out *naistrix.OutputWriter) error {
+					tasks := out.Tasks(naistrix.TasksWithConcurrency(1))
+					for _, name := range tt.order {
+						tasks.Add(name, tt.tasks[name])
+					}
+					runErr = tasks.Run(ctx)
+					return nil
+				},
+			})
+			if err != nil {
+				t.Fatalf("unable to add command: %v", err)
+			}
+
+			if err := app.Run(naistrix.RunWithArgs(append([]string{"test"}, tt.args...))); err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			if tt.expectedErr == "" && runErr != nil {
+				t.Errorf("expected no error from tasks, got %v", runErr)
+			} else if tt.expectedErr != "" && (runErr == nil || runErr.Error() != tt.expectedErr) {
+				t.Errorf("expected error from tasks %q, got %v", tt.expectedErr, runErr)
+			}
+
+			if actual := errBuf.String(); actual != tt.expectedOutput {
+				t.Errorf("expected error output %q, got %q", tt.expectedOutput, actual)
+			}
+		})
+	}
+}
+
+func TestOutputWriter_TasksInTerminal(t *testing.T) {
+	restore := naistrix.SetTerminalSize(func(io.Writer) (int, int) { return 80, 24 })
+	defer restore()
+
+	var buf, errBuf bytes.Buffer
+	app, _, err := naistrix.NewApplication(
+		"app",
+		"title",
+		"v0.0.0",
+		naistrix.ApplicationWithWriter(&buf),
+		naistrix.ApplicationWithErrorWriter(&errBuf),
+	)
+	if err != nil {
+		t.Fatalf("unable to create application: %v", err)
+	}
+
+	err = app.AddCommand(&naistrix.Command{
+		Name:  "test",
+		Title: "Test command",
+		RunFunc: func(ctx context.Context, _ *naistrix.Arguments, out *naistrix.OutputWriter) error {
+			return out.Tasks().Add("cluster-a", func(context.Context) error { return nil }).Run(ctx)
+		},
+	})
+	if err != nil {
+		t.Fatalf("unable to add command: %v", err)
+	}
+
+	if err := app.Run(naistrix.RunWithArgs([]string{"test"})); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if buf.Len() > 0 {
+		t.Errorf("expected no output, got %q", buf.String())
+	}
+
+	// the status lines are rewritten in place for every change of status
+	errOutput := errBuf.String()
+	if expected, actual := 2, strings.Count(errOutput, "\x1b[1F\x1b[J"); actual != expected {
+		t.Errorf("expected the status lines to be rewritten %d times, got %d times in %q", expected, actual, errOutput)
+	}
+
+	if expected := "cluster-a: done"; !strings.Contains(errOutput, expected) {
+		t.Errorf("expected error output to contain %q, got %q", expected, errOutput)
+	}
+
+	if expected := "1 tasks: 1 done, 0 failed\n"; !strings.HasSuffix(errOutput, expected) {
+		t.Errorf("expected error output to end with %q, got %q", expected, errOutput)
+	}
+}
+
+func TestOutputWriter_TasksRunConcurrently(t *testing.T) {
+	pterm.DisableStyling()
+	defer pterm.EnableStyling()
+
+	var errBuf bytes.Buffer
+	app, _, err := naistrix.NewApplication(
+		"app",
+		"title",
+		"v0.0.0",
+		naistrix.ApplicationWithWriter(&bytes.Buffer{}),
+		naistrix.ApplicationWithErrorWriter(&errBuf),
+	)
+	if err != nil {
+		t.Fatalf("unable to create application: %v", err)
+	}
+
+	const numTasks = 3
+	err = app.AddCommand(&naistrix.Command{
+		Name:  "test",
+		Title: "Test command",
+		RunFunc: func(ctx context.Context, _ *naistrix.Arguments, out *naistrix.OutputWriter) error {
+			// every task waits for all tasks to be running, which only finishes if the tasks run concurrently
+			var started sync.WaitGroup
+			started.Add(numTasks)
+
+			tasks := out.Tasks()
+			for _, name := range []string{"a", "b", "c"} {
+				tasks.Add(name, func(context.Context) error {
+					started.Done()
+					started.Wait()
+					return nil
+				})
+			}
+			return tasks.Run(ctx)
+		},
+	})
+	if err != nil {
+		t.Fatalf("unable to add command: %v", err)
+	}
+
+	if err := app.Run(naistrix.RunWithArgs([]string{"test"})); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(errBuf.String()), "\n")
+	if expected := 2*numTasks + 1; len(lines) != expected {
+		t.Fatalf("expected %d lines of output, got %q", expected, errBuf.String())
+	}
+
+	if expected, actual := "3 tasks: 3 done, 0 failed", lines[len(lines)-1]; actual != expected {
+		t.Errorf("expected summary %q, got %q", expected, actual)
+	}
+}
+
+func TestOutputWriter_TasksCancelled(t *testing.T) {
+	pterm.DisableStyling()
+	defer pterm.EnableStyling()
+
+	var errBuf bytes.Buffer
+	app, _, err := naistrix.NewApplication(
+		"app",
+		"title",
+		"v0.0.0",
+		naistrix.ApplicationWithWriter(&bytes.Buffer{}),
+		naistrix.ApplicationWithErrorWriter(&errBuf),
+	)
+	if err != nil {
+		t.Fatalf("unable to create application: %v", err)
+	}
+
+	var runErr error
+	err = app.AddCommand(&naistrix.Command{
+		Name:  "test",
+		Title: "Test command",
+		RunFunc: func(ctx context.Context, _ *naistrix.Arguments, out *naistrix.OutputWriter) error {
+			ctx, cancel := context.WithCancel(ctx)
+			runErr = out.Tasks(naistrix.TasksWithConcurrency(1)).
+				Add("first", func(context.Context) error {
+					cancel()
+					return nil
+				}).
+				Add("second", func(context.Context) error {
+					t.Error("expected task not to run after the context is cancelled")
+					return nil
+				}).
+				Run(ctx)
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("unable to add command: %v", err)
+	}
+
+	if err := app.Run(naistrix.RunWithArgs([]string{"test"})); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if !errors.Is(runErr, context.Canceled) {
+		t.Errorf("expected error to wrap context.Canceled, got %v", runErr)
+	}
+
+	expected := "first: running\nfirst: done\nsecond: failed: context canceled\n2 tasks: 1 done, 1 failed\n"
+	if actual := errBuf.String(); actual != expected {
+		t.Errorf("expected error output %q, got %q", expected, actual)
+	}
+}
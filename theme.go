@@ -0,0 +1,71 @@
+package naistrix
+
+import (
+	"fmt"
+	"maps"
+
+	"github.com/nais/naistrix/internal/color"
+)
+
+// themeConfigKey is the key in the configuration file used by the end-user to override the styles of the theme.
+const themeConfigKey = "theme"
+
+// Theme defines the styles of the inline tags that can be used in output, like "<info>text</info>", keyed by the name
+// of the tag. A style is a comma-separated list of colors and text attributes, like for instance "bold,light-red".
+//
+// The available colors are default, black, red, green, yellow, blue, magenta, cyan, white and gray, and the light
+// variants of all colors but gray, like light-red. The available text attributes are bold, faint, italic and underline.
+//
+// Tags can be nested, like in "<bold>Run <code>app deploy</code></bold>". Tag names must start with a lower case
+// letter, and can contain lower case letters, digits and dashes. The tags are only colorized in text written by the
+// application, like the format strings of [OutputWriter.Printf] and the string arguments of [OutputWriter.Println], and
+// not in data from elsewhere, like the values rendered using [OutputWriter.Render].
+type Theme map[string]string
+
+// DefaultTheme returns the theme used unless another theme is set using the [ApplicationWithTheme] option.
+func DefaultTheme() Theme {
+	return Theme{
+		"info":    "light-cyan",
+		"warn":    "yellow",
+		"error":   "light-red",
+		"success": "green",
+		"muted":   "gray",
+		"bold":    "bold",
+		"code":    "cyan",
+	}
+}
+
+// ApplicationWithTheme sets the styles of the inline tags used in output. The styles in the provided theme are added to
+// the [DefaultTheme], replacing the styles of tags with the same name. The end-user can override the styles in the
+// configuration file, for instance using "defaults set theme.info blue".
+func ApplicationWithTheme(theme Theme) ApplicationOptionFunc {
+	return func(a *Application) {
+		maps.Copy(a.theme, theme)
+	}
+}
+
+// merge returns a copy of the theme, with the styles of overrides added.
+func (t Theme) merge(overrides map[string]string) Theme {
+	ret := maps.Clone(t)
+	maps.Copy(ret, overrides)
+	return ret
+}
+
+// compile parses the styles of the theme.
+func (t Theme) compile() (color.Theme, error) {
+	ret := make(color.Theme, len(t))
+	for name, style := range t {
+		if !color.ValidTagName(name) {
+			return nil, fmt.Errorf("invalid tag name %q", name)
+		}
+
+		colors, err := color.ParseStyle(style)
+		if err != nil {
+			return nil, fmt.Errorf("invalid style for the %q tag: %w", name, err)
+		}
+
+		ret[name] = colors
+	}
+
+	return ret, nil
+}
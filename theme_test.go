@@ -0,0 +1,100 @@
+package naistrix_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nais/naistrix"
+)
+
+func TestApplicationWithTheme(t *testing.T) {
+	tests := []struct {
+		name           string
+		theme          naistrix.Theme
+		config         string
+		expectedOutput string
+		expectedErr    string
+	}{
+		{
+			name:           "default theme",
+			expectedOutput: "info <highlight>custom</highlight>\n",
+		},
+		{
+			name:           "custom tag",
+			theme:          naistrix.Theme{"highlight": "bold,magenta"},
+			expectedOutput: "info custom\n",
+		},
+		{
+			name:           "custom tag from config file",
+			config:         "theme:\n  highlight: underline\n",
+			expectedOutput: "info custom\n",
+		},
+		{
+			name:        "invalid style in config file",
+			theme:       naistrix.Theme{"highlight": "bold"},
+			config:      "theme:\n  info: purple\n",
+			expectedErr: `Invalid theme in the configuration file: invalid style for the "info" tag: unknown color "purple"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configPath := filepath.Join(t.TempDir(), "config.yaml")
+			if tt.config != "" {
+				if err := os.WriteFile(configPath, []byte(tt.config), 0o600); err != nil {
+					t.Fatalf("unable to write config file: %v", err)
+				}
+			}
+
+			var buf bytes.Buffer
+			app, _, err := naistrix.NewApplication(
+				"app",
+				"title",
+				"v0.0.0",
+				naistrix.ApplicationWithWriter(&buf),
+				naistrix.ApplicationWithTheme(tt.theme),
+			)
+			if err != nil {
+				t.Fatalf("unable to create application: %v", err)
+			}
+
+			err = app.AddCommand(&naistrix.Command{
+				Name:  "test",
+				Title: "Test command",
+				RunFunc: func(_ context.Context, _ *naistrix.Arguments, out *naistrix.OutputWriter) error {
+					out.Println("<info>info</info> <highlight>custom</highlight>")
+					return nil
+				},
+			})
+			if err != nil {
+				t.Fatalf("unable to add command: %v", err)
+			}
+
+			err = app.Run(naistrix.RunWithArgs([]string{"test", "--color=never", "--config", configPath}))
+			if tt.expectedErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.expectedErr) {
+					t.Fatalf("expected error to contain %q, got %v", tt.expectedErr, err)
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			if actual := buf.String(); actual != tt.expectedOutput {
+				t.Errorf("expected output %q, got %q", tt.expectedOutput, actual)
+			}
+		})
+	}
+}
+
+func TestApplicationWithTheme_Invalid(t *testing.T) {
+	for _, theme := range []naistrix.Theme{{"info": "purple"}, {"Info": "red"}} {
+		if _, _, err := naistrix.NewApplication("app", "title", "v0.0.0", naistrix.ApplicationWithTheme(theme)); err == nil {
+			t.Errorf("expected error for theme %v", theme)
+		}
+	}
+}
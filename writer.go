@@ -3,6 +3,8 @@ package naistrix
 import (
 	"fmt"
 	"io"
+	"os"
+	"slices"
 
 	"github.com/nais/naistrix/internal/color"
 	"github.com/nais/naistrix/output"
@@ -10,23 +12,159 @@ import (
 )
 
 // OutputWriter is used to write output to the user, with support for different verbosity levels and output formats.
+//
+// Data, like the output from [OutputWriter.Println] and the renderers, is written to the main writer, while diagnostic
+// and status messages, like warnings and debug output, are written to the error writer. This makes it possible to pipe
+// the data to other programs without the diagnostic messages getting in the way.
 type OutputWriter struct {
-	writer io.Writer
-	level  *Count
+	writer    io.Writer
+	errWriter io.Writer
+	level     *Count
+
+	// quiet tells whether the end-user has asked for quiet output using the global --quiet flag.
+	quiet *bool
+
+	// format is the output format selected by the end-user, used by Render.
+	format *string
+
+	// formats holds the registered output formats available to Render.
+	formats outputFormats
+
+	// supportedFormats are the output formats supported by the command being executed. The first format is used as the
+	// default when the end-user has not selected a format. When empty, all registered formats are supported.
+	supportedFormats []OutputFormat
+
+	// columns, sortBy and filters are the table column selection, sorting and filters set by the end-user, applied to
+	// all tables.
+	columns, sortBy, filters *[]string
+
+	// pager tells whether long output should be shown in a pager, and noPager whether the end-user has disabled the
+	// pager using the global --no-pager flag.
+	pager   bool
+	noPager *bool
+
+	// indicators keeps track of the spinners and progress bars started while running a command, so they can be
+	// stopped when the command returns.
+	indicators *indicators
 }
 
-// NewOutputWriter creates a new output writer.
+// NewOutputWriter creates a new output writer. Diagnostic and status messages are written to [os.Stderr].
 func NewOutputWriter(writer io.Writer, level *Count) *OutputWriter {
 	pterm.SetDefaultOutput(writer)
 	return &OutputWriter{
-		writer: writer,
-		level:  level,
+		writer:    writer,
+		errWriter: os.Stderr,
+		level:     level,
+		quiet:     new(false),
+		format:    new(""),
+		formats:   defaultOutputFormats(),
+		columns:   new([]string{}),
+		sortBy:    new([]string{}),
+		filters:   new([]string{}),
+		noPager:   new(false),
+	}
+}
+
+// forCommand returns a copy of the output writer that is restricted to the output formats supported by a command.
+func (w *OutputWriter) forCommand(formats []OutputFormat) *OutputWriter {
+	cp := *w
+	cp.supportedFormats = formats
+	return &cp
+}
+
+// Render renders v using the output format selected by the end-user with the global --output flag. If no format has
+// been selected, the first output format declared by the command is used, falling back to the table format.
+//
+// The value can be an [iter.Seq], or an [iter.Seq2] where the second value is an error. Items are then streamed as they
+// are yielded when using the ndjson, table or wide formats, while other formats collect all items before rendering
+// them. See [output.NDJSON.Render] and [output.Table.Stream] for details.
+//
+// When the table, wide, csv or tsv format is used, the columns, sorting and filters set by the end-user with the global
+// --columns, --sort and --filter flags are applied to the rows. Tables are also limited to the width of the terminal.
+//
+// Additional output formats can be registered using the [ApplicationWithOutputFormat] option.
+func (w *OutputWriter) Render(v any) error {
+	name, arg := parseOutputFormat(*w.format)
+	if name == "" {
+		name = defaultOutputFormat
+		if len(w.supportedFormats) > 0 {
+			name = w.supportedFormats[0]
+		}
+	}
+
+	if len(w.supportedFormats) > 0 && !slices.Contains(w.supportedFormats, name) {
+		return Errorf("Unsupported output format %q, must be one of: %s", name, joinOutputFormats(w.supportedFormats, ", "))
+	}
+
+	fn, ok := w.formats[name]
+	if !ok {
+		return Errorf("Unknown output format %q, must be one of: %s", name, joinOutputFormats(w.formats.names(), ", "))
+	}
+
+	r, err := fn(w.writer, arg)
+	if err != nil {
+		return renderError(err)
+	}
+
+	switch r := r.(type) {
+	case *output.Table:
+		for _, opt := range w.tableOptions() {
+			opt(r)
+		}
+	case *output.CSV:
+		output.CSVWithColumns(*w.columns...)(r)
+		output.CSVWithSortBy(*w.sortBy...)(r)
+		output.CSVWithFilters(*w.filters...)(r)
+	case *output.TSV:
+		output.TSVWithColumns(*w.columns...)(r)
+		output.TSVWithSortBy(*w.sortBy...)(r)
+		output.TSVWithFilters(*w.filters...)(r)
 	}
+
+	switch r.(type) {
+	case *output.NDJSON, *output.Table:
+	default:
+		if v, err = output.Collect(v); err != nil {
+			return err
+		}
+	}
+
+	// queries and templates are validated before the command is run, but can still fail when rendering
+	return renderError(r.Render(v))
 }
 
-// Table creates a new table that can be rendered to the destination.
+// Table creates a new table that can be rendered to the destination. The columns, sorting and filters set by the
+// end-user with the global --columns, --sort and --filter flags take precedence over the provided options. When the
+// destination is a terminal, the table is limited to the width of the terminal.
 func (w *OutputWriter) Table(opts ...output.TableOptionFunc) *output.Table {
-	return output.NewTable(w.writer, opts...)
+	return output.NewTable(w.writer, append(slices.Clone(opts), w.tableOptions()...)...)
+}
+
+// tableOptions returns the table options for the columns, sorting and filters set by the end-user. Wide columns are
+// included when the end-user has selected the wide output format, and tables are limited to the width of the terminal.
+func (w *OutputWriter) tableOptions() []output.TableOptionFunc {
+	opts := make([]output.TableOptionFunc, 0)
+	if name, _ := parseOutputFormat(*w.format); name == OutputFormatWide {
+		opts = append(opts, output.TableWithWideColumns())
+	}
+
+	if width, _ := terminalSize(w.writer); width > 0 {
+		opts = append(opts, output.TableWithMaxWidth(width))
+	}
+
+	if len(*w.columns) > 0 {
+		opts = append(opts, output.TableWithColumns(*w.columns...))
+	}
+
+	if len(*w.sortBy) > 0 {
+		opts = append(opts, output.TableWithSortBy(*w.sortBy...))
+	}
+
+	if len(*w.filters) > 0 {
+		opts = append(opts, output.TableWithFilters(*w.filters...))
+	}
+
+	return opts
 }
 
 // JSON creates a new JSON output that can be rendered to the destination.
@@ -35,126 +173,213 @@ func (w *OutputWriter) JSON(opts ...output.JSONOptionFunc) *output.JSON {
 }
 
 // YAML creates a new YAML output that can be rendered to the destination.
-func (w *OutputWriter) YAML() *output.YAML {
-	return output.NewYAML(w.writer)
+func (w *OutputWriter) YAML(opts ...output.YAMLOptionFunc) *output.YAML {
+	return output.NewYAML(w.writer, opts...)
 }
 
-// Successln writes a line of "successful" output to the destination, appending a newline at the end. Spaces are added
-// between arguments. This outputs in all verbosity levels.
+// NDJSON creates a new streaming NDJSON output that can be rendered to the destination. Use [output.NDJSON.Write] to
+// write items one at a time, or pass a slice or a sequence to [output.NDJSON.Render].
+func (w *OutputWriter) NDJSON() *output.NDJSON {
+	return output.NewNDJSON(w.writer)
+}
+
+// CSV creates a new CSV output that can be rendered to the destination.
+func (w *OutputWriter) CSV(opts ...output.CSVOptionFunc) *output.CSV {
+	return output.NewCSV(w.writer, opts...)
+}
+
+// TSV creates a new TSV output that can be rendered to the destination.
+func (w *OutputWriter) TSV(opts ...output.TSVOptionFunc) *output.TSV {
+	return output.NewTSV(w.writer, opts...)
+}
+
+// Template creates a new Go template output that can be rendered to the destination. See [output.NewTemplate] for the
+// functions available to the template.
+func (w *OutputWriter) Template(text string, opts ...output.TemplateOptionFunc) *output.Template {
+	return output.NewTemplate(w.writer, text, opts...)
+}
+
+// Tree creates a new tree output that can be rendered to the destination. See [output.Tree.Render] for the supported
+// values.
+func (w *OutputWriter) Tree() *output.Tree {
+	return output.NewTree(w.writer)
+}
+
+// Describe creates a new output for the fields of a single object that can be rendered to the destination.
+func (w *OutputWriter) Describe(opts ...output.DescribeOptionFunc) *output.Describe {
+	return output.NewDescribe(w.writer, opts...)
+}
+
+// Diff creates a new output for the differences between two versions of a value that can be rendered to the
+// destination. Use [output.Diff.RenderChange] to render the differences.
+func (w *OutputWriter) Diff(opts ...output.DiffOptionFunc) *output.Diff {
+	return output.NewDiff(w.writer, opts...)
+}
+
+// Markdown creates a new output that formats markdown for the terminal, and that can be rendered to the destination.
+func (w *OutputWriter) Markdown() *output.Markdown {
+	return output.NewMarkdown(w.writer)
+}
+
+// IsQuiet reports whether the end-user has asked for quiet output using the global --quiet flag. In quiet mode the
+// informational, status and verbose output methods are no-ops, while data written with Println, Printf and the
+// renderers is still written. Commands can use this to skip hints and status messages written with Println or Printf.
+func (w *OutputWriter) IsQuiet() bool {
+	return *w.quiet
+}
+
+// IsVerbose reports whether the end-user has asked for verbose output using the global --verbose flag, which means that
+// Verboseln and Verbosef write output. Commands can use this to skip building verbose output that is not shown.
+func (w *OutputWriter) IsVerbose() bool {
+	return !*w.quiet && *w.level >= OutputVerbosityLevelVerbose
+}
+
+// Successln writes a line of "successful" output to the error destination, appending a newline at the end. Spaces are
+// added between arguments. This outputs in all verbosity levels, but not in quiet mode.
 func (w *OutputWriter) Successln(a ...any) *OutputWriter {
-	pterm.Success.WithWriter(w.writer).Println(a...)
+	if *w.quiet {
+		return w
+	}
+
+	pterm.Success.WithWriter(w.errWriter).Println(a...)
 	return w
 }
 
-// Successf writes formatted "successful" output to the destination. This outputs in all verbosity levels.
+// Successf writes formatted "successful" output to the error destination. This outputs in all verbosity levels, but not
+// in quiet mode.
 func (w *OutputWriter) Successf(format string, a ...any) *OutputWriter {
-	pterm.Success.WithWriter(w.writer).Printf(format, a...)
+	if *w.quiet {
+		return w
+	}
+
+	pterm.Success.WithWriter(w.errWriter).Printf(format, a...)
 	return w
 }
 
-// Infoln writes a line of informational output to the destination, appending a newline at the end. Spaces are added
-// between arguments. This outputs in all verbosity levels.
+// Infoln writes a line of informational output to the error destination, appending a newline at the end. Spaces are
+// added between arguments. This outputs in all verbosity levels, but not in quiet mode.
 func (w *OutputWriter) Infoln(a ...any) *OutputWriter {
-	pterm.Info.WithWriter(w.writer).Println(a...)
+	if *w.quiet {
+		return w
+	}
+
+	pterm.Info.WithWriter(w.errWriter).Println(a...)
 	return w
 }
 
-// Infof writes formatted informational output to the destination. This outputs in all verbosity levels.
+// Infof writes formatted informational output to the error destination. This outputs in all verbosity levels, but not
+// in quiet mode.
 func (w *OutputWriter) Infof(format string, a ...any) *OutputWriter {
-	pterm.Info.WithWriter(w.writer).Printf(format, a...)
+	if *w.quiet {
+		return w
+	}
+
+	pterm.Info.WithWriter(w.errWriter).Printf(format, a...)
 	return w
 }
 
-// Warnln writes a line of warning output to the destination, appending a newline at the end. Spaces are added
-// between arguments. This outputs in all verbosity levels.
+// Warnln writes a line of warning output to the error destination, appending a newline at the end. Spaces are added
+// between arguments. This outputs in all verbosity levels, but not in quiet mode.
 func (w *OutputWriter) Warnln(a ...any) *OutputWriter {
-	pterm.Warning.WithWriter(w.writer).Println(a...)
+	if *w.quiet {
+		return w
+	}
+
+	pterm.Warning.WithWriter(w.errWriter).Println(a...)
 	return w
 }
 
-// Warnf writes formatted warning output to the destination. This outputs in all verbosity levels.
+// Warnf writes formatted warning output to the error destination. This outputs in all verbosity levels, but not in
+// quiet mode.
 func (w *OutputWriter) Warnf(format string, a ...any) *OutputWriter {
-	pterm.Warning.WithWriter(w.writer).Printf(format, a...)
+	if *w.quiet {
+		return w
+	}
+
+	pterm.Warning.WithWriter(w.errWriter).Printf(format, a...)
 	return w
 }
 
-// Errorln writes a line of error output to the destination, appending a newline at the end. Spaces are added
+// Errorln writes a line of error output to the error destination, appending a newline at the end. Spaces are added
 // between arguments. This outputs in all verbosity levels.
 func (w *OutputWriter) Errorln(a ...any) *OutputWriter {
-	pterm.Error.WithWriter(w.writer).Println(a...)
+	pterm.Error.WithWriter(w.errWriter).Println(a...)
 	return w
 }
 
-// Errorf writes formatted error output to the destination. This outputs in all verbosity levels.
+// Errorf writes formatted error output to the error destination. This outputs in all verbosity levels.
 func (w *OutputWriter) Errorf(format string, a ...any) *OutputWriter {
-	pterm.Error.WithWriter(w.writer).Printf(format, a...)
+	pterm.Error.WithWriter(w.errWriter).Printf(format, a...)
 	return w
 }
 
 // Println writes a line of output to the destination, appending a newline at the end. Spaces are added between
-// arguments. This outputs in all verbosity levels.
+// arguments. Inline color tags, see [Theme], are colorized in arguments of type string. This outputs in all verbosity
+// levels.
 func (w *OutputWriter) Println(a ...any) *OutputWriter {
-	pterm.Println(color.ColorizeAny(a)...)
+	pterm.Fprint(w.writer, pterm.Sprintln(color.ColorizeAny(a)...))
 	return w
 }
 
-// Printf writes formatted output to the destination. This outputs in all verbosity levels.
+// Printf writes formatted output to the destination. Inline color tags, see [Theme], are colorized in the format
+// string, but not in the arguments. This outputs in all verbosity levels.
 func (w *OutputWriter) Printf(format string, a ...any) *OutputWriter {
-	pterm.Printf(color.Colorize(format), a...)
+	pterm.Fprint(w.writer, pterm.Sprintf(color.Colorize(format), a...))
 	return w
 }
 
-// Verboseln writes a line of verbose output to the destination, appending a newline at the end. Spaces are added
+// Verboseln writes a line of verbose output to the error destination, appending a newline at the end. Spaces are added
 // between arguments. This outputs in [OutputVerbosityLevelVerbose] and higher levels.
 func (w *OutputWriter) Verboseln(a ...any) *OutputWriter {
-	if *w.level < OutputVerbosityLevelVerbose {
+	if !w.IsVerbose() {
 		return w
 	}
 
-	_, _ = fmt.Fprintln(w.writer, color.ColorizeAny(a)...)
+	_, _ = fmt.Fprintln(w.errWriter, color.ColorizeAny(a)...)
 	return w
 }
 
-// Verbosef writes formatted verbose output to the destination. This outputs in [OutputVerbosityLevelVerbose] and higher
-// levels.
+// Verbosef writes formatted verbose output to the error destination. This outputs in [OutputVerbosityLevelVerbose] and
+// higher levels.
 func (w *OutputWriter) Verbosef(format string, a ...any) *OutputWriter {
-	if *w.level < OutputVerbosityLevelVerbose {
+	if !w.IsVerbose() {
 		return w
 	}
 
-	_, _ = fmt.Fprintf(w.writer, color.Colorize(format), a...)
+	_, _ = fmt.Fprintf(w.errWriter, color.Colorize(format), a...)
 	return w
 }
 
-// Debugln writes a line of debug output to the destination, appending a newline at the end. Spaces are added between
-// arguments. This outputs in [OutputVerbosityLevelDebug] and higher levels.
+// Debugln writes a line of debug output to the error destination, appending a newline at the end. Spaces are added
+// between arguments. This outputs in [OutputVerbosityLevelDebug] and higher levels.
 func (w *OutputWriter) Debugln(a ...any) *OutputWriter {
-	if *w.level < OutputVerbosityLevelDebug {
+	if *w.quiet || *w.level < OutputVerbosityLevelDebug {
 		return w
 	}
 
 	pterm.EnableDebugMessages()
 	defer pterm.DisableDebugMessages()
-	pterm.Debug.WithWriter(w.writer).Println(color.ColorizeAny(a)...)
+	pterm.Debug.WithWriter(w.errWriter).Println(color.ColorizeAny(a)...)
 	return w
 }
 
-// Debugf writes formatted debug output to the destination. This outputs in [OutputVerbosityLevelDebug] and higher levels.
+// Debugf writes formatted debug output to the error destination. This outputs in [OutputVerbosityLevelDebug] and higher
+// levels.
 func (w *OutputWriter) Debugf(format string, a ...any) *OutputWriter {
-	if *w.level < OutputVerbosityLevelDebug {
+	if *w.quiet || *w.level < OutputVerbosityLevelDebug {
 		return w
 	}
 
 	pterm.EnableDebugMessages()
 	defer pterm.DisableDebugMessages()
-	pterm.Debug.WithWriter(w.writer).Printf(color.Colorize(format), a...)
+	pterm.Debug.WithWriter(w.errWriter).Printf(color.Colorize(format), a...)
 	return w
 }
 
-// Traceln writes a line of trace output to the destination, appending a newline at the end. Spaces are added between
-// arguments. This outputs in [OutputVerbosityLevelTrace] level.
+// Traceln writes a line of trace output to the error destination, appending a newline at the end. Spaces are added
+// between arguments. This outputs in [OutputVerbosityLevelTrace] level.
 func (w *OutputWriter) Traceln(a ...any) *OutputWriter {
-	if *w.level < OutputVerbosityLevelTrace {
+	if *w.quiet || *w.level < OutputVerbosityLevelTrace {
 		return w
 	}
 
@@ -162,13 +387,13 @@ func (w *OutputWriter) Traceln(a ...any) *OutputWriter {
 	defer pterm.DisableDebugMessages()
 	prefix := pterm.Debug.Prefix
 	prefix.Text = " TRACE "
-	pterm.Debug.WithWriter(w.writer).WithPrefix(prefix).Println(color.ColorizeAny(a)...)
+	pterm.Debug.WithWriter(w.errWriter).WithPrefix(prefix).Println(color.ColorizeAny(a)...)
 	return w
 }
 
-// Tracef writes formatted trace output to the destination. This outputs in [OutputVerbosityLevelTrace] level.
+// Tracef writes formatted trace output to the error destination. This outputs in [OutputVerbosityLevelTrace] level.
 func (w *OutputWriter) Tracef(format string, a ...any) *OutputWriter {
-	if *w.level < OutputVerbosityLevelTrace {
+	if *w.quiet || *w.level < OutputVerbosityLevelTrace {
 		return w
 	}
 
@@ -176,6 +401,6 @@ func (w *OutputWriter) Tracef(format string, a ...any) *OutputWriter {
 	defer pterm.DisableDebugMessages()
 	prefix := pterm.Debug.Prefix
 	prefix.Text = " TRACE "
-	pterm.Debug.WithWriter(w.writer).WithPrefix(prefix).Printf(color.Colorize(format), a...)
+	pterm.Debug.WithWriter(w.errWriter).WithPrefix(prefix).Printf(color.Colorize(format), a...)
 	return w
 }
@@ -3,38 +3,42 @@ package naistrix_test
 import (
 	"bytes"
 	"context"
+	"errors"
+	"fmt"
+	"io"
 	"strings"
 	"testing"
 
 	"github.com/nais/naistrix"
+	"github.com/nais/naistrix/output"
 	"github.com/pterm/pterm"
 )
 
 func TestOutputWriter_ConditionalOutput(t *testing.T) {
 	tests := []struct {
-		name     string
-		expected string
-		flags    []string
+		name        string
+		expectedErr string
+		flags       []string
 	}{
 		{
-			name:     "regular output",
-			expected: "normal: n1 n2\n",
-			flags:    []string{},
+			name:        "regular output",
+			expectedErr: "",
+			flags:       []string{},
 		},
 		{
-			name:     "verbose output",
-			expected: "normal: n1 n2\nverbose: v1 v2\nverbosef: v1\n",
-			flags:    []string{"-v"},
+			name:        "verbose output",
+			expectedErr: "verbose: v1 v2\nverbosef: v1\n",
+			flags:       []string{"-v"},
 		},
 		{
-			name:     "debug output",
-			expected: "normal: n1 n2\nverbose: v1 v2\nverbosef: v1\nDEBUG: debug: d1 d2\nDEBUG: debugf: d1\n",
-			flags:    []string{"-vv"},
+			name:        "debug output",
+			expectedErr: "verbose: v1 v2\nverbosef: v1\nDEBUG: debug: d1 d2\nDEBUG: debugf: d1\n",
+			flags:       []string{"-vv"},
 		},
 		{
-			name:     "trace output",
-			expected: "normal: n1 n2\nverbose: v1 v2\nverbosef: v1\nDEBUG: debug: d1 d2\nDEBUG: debugf: d1\nTRACE: trace: t1 t2\nTRACE: tracef: t1\n",
-			flags:    []string{"-vvv"},
+			name:        "trace output",
+			expectedErr: "verbose: v1 v2\nverbosef: v1\nDEBUG: debug: d1 d2\nDEBUG: debugf: d1\nTRACE: trace: t1 t2\nTRACE: tracef: t1\n",
+			flags:       []string{"-vvv"},
 		},
 	}
 
@@ -43,8 +47,14 @@ func TestOutputWriter_ConditionalOutput(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			var buf bytes.Buffer
-			app, _, err := naistrix.NewApplication("app", "title", "v0.0.0", naistrix.ApplicationWithWriter(&buf))
+			var buf, errBuf bytes.Buffer
+			app, _, err := naistrix.NewApplication(
+				"app",
+				"title",
+				"v0.0.0",
+				naistrix.ApplicationWithWriter(&buf),
+				naistrix.ApplicationWithErrorWriter(&errBuf),
+			)
 			if err != nil {
 				t.Fatalf("unable to create application: %v", err)
 			}
@@ -72,19 +82,83 @@ func TestOutputWriter_ConditionalOutput(t *testing.T) {
 				t.Fatalf("expected no error, got %v", err)
 			}
 
-			if actual := buf.String(); !strings.Contains(actual, tt.expected) {
-				t.Errorf("expected output to contain %q, got %q", tt.expected, actual)
+			if actual, expected := buf.String(), "normal: n1 n2\n"; actual != expected {
+				t.Errorf("expected output to be %q, got %q", expected, actual)
+			}
+
+			if actual := errBuf.String(); !strings.Contains(actual, tt.expectedErr) {
+				t.Errorf("expected error output to contain %q, got %q", tt.expectedErr, actual)
 			}
 		})
 	}
 }
 
+func TestOutputWriter_Quiet(t *testing.T) {
+	pterm.DisableStyling()
+	defer pterm.EnableStyling()
+
+	run := func(args ...string) (string, string, error) {
+		t.Helper()
+
+		var buf, errBuf bytes.Buffer
+		app, _, err := naistrix.NewApplication(
+			"app",
+			"title",
+			"v0.0.0",
+			naistrix.ApplicationWithWriter(&buf),
+			naistrix.ApplicationWithErrorWriter(&errBuf),
+		)
+		if err != nil {
+			t.Fatalf("unable to create application: %v", err)
+		}
+
+		err = app.AddCommand(&naistrix.Command{
+			Name:  "test",
+			Title: "Test command",
+			RunFunc: func(_ context.Context, _ *naistrix.Arguments, out *naistrix.OutputWriter) error {
+				out.Successln("success").Infoln("info").Warnln("warning").Verboseln("verbose").Debugln("debug")
+				out.Errorln("error")
+				return out.JSON().Render([]string{"data"})
+			},
+		})
+		if err != nil {
+			t.Fatalf("unable to add command: %v", err)
+		}
+
+		err = app.Run(naistrix.RunWithArgs(args))
+		return buf.String(), errBuf.String(), err
+	}
+
+	out, errOut, err := run("test", "--quiet")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if expected := "[\"data\"]\n"; out != expected {
+		t.Errorf("expected output to be %q, got %q", expected, out)
+	}
+
+	if expected := "ERROR: error\n"; errOut != expected {
+		t.Errorf("expected error output to be %q, got %q", expected, errOut)
+	}
+
+	if _, _, err := run("test", "--quiet", "-v"); err == nil || !strings.Contains(err.Error(), "can not be used together") {
+		t.Fatalf("expected error when combining --quiet and --verbose, got %v", err)
+	}
+}
+
 func TestOutputWriter_OutputStyles(t *testing.T) {
 	pterm.DisableStyling()
 	defer pterm.EnableStyling()
 
-	var buf bytes.Buffer
-	app, _, err := naistrix.NewApplication("app", "title", "v0.0.0", naistrix.ApplicationWithWriter(&buf))
+	var buf, errBuf bytes.Buffer
+	app, _, err := naistrix.NewApplication(
+		"app",
+		"title",
+		"v0.0.0",
+		naistrix.ApplicationWithWriter(&buf),
+		naistrix.ApplicationWithErrorWriter(&errBuf),
+	)
 	if err != nil {
 		t.Fatalf("unable to create application: %v", err)
 	}
@@ -105,7 +179,8 @@ func TestOutputWriter_OutputStyles(t *testing.T) {
 				Println("An <info>informational</info> message.").
 				Println("A <warn>warning</warn> message.").
 				Println("An <error>error</error> message.").
-				Println("Some <info>info</info>, a <warn>warning</warn> and an <error>error</error>.")
+				Println("Some <info>info</info>, a <warn>warning</warn> and an <error>error</error>.").
+				Println("Values of other types are left as is:", fmt.Errorf("<bold>%s</bold>", "bold"))
 
 			return nil
 		},
@@ -118,7 +193,13 @@ func TestOutputWriter_OutputStyles(t *testing.T) {
 		t.Fatalf("expected no error, got %v", err)
 	}
 
-	output := buf.String()
+	expected := "An informational message.\nA warning message.\nAn error message.\nSome info, a warning and an error.\n" +
+		"Values of other types are left as is: <bold>bold</bold>\n"
+	if output := buf.String(); output != expected {
+		t.Errorf("expected output to be %q, got %q", expected, output)
+	}
+
+	errOutput := errBuf.String()
 	expectedSubstrings := []string{
 		"SUCCESS: some success",
 		"SUCCESS: more success",
@@ -128,15 +209,441 @@ func TestOutputWriter_OutputStyles(t *testing.T) {
 		"WARNING: more warning",
 		"ERROR: some error",
 		"ERROR: more error",
-		"An informational message.",
-		"A warning message.",
-		"An error message.",
-		"Some info, a warning and an error.",
 	}
 
 	for _, substr := range expectedSubstrings {
-		if !strings.Contains(output, substr) {
-			t.Errorf("expected output to contain %q, got %q", substr, output)
+		if !strings.Contains(errOutput, substr) {
+			t.Errorf("expected error output to contain %q, got %q", substr, errOutput)
+		}
+	}
+}
+
+func TestOutputWriter_Render(t *testing.T) {
+	type row struct {
+		Name string
+	}
+
+	customFormat := naistrix.ApplicationWithOutputFormat("custom", func(w io.Writer, arg string) (output.Renderer, error) {
+		if arg == "" {
+			return nil, naistrix.Errorf("missing argument")
+		}
+		return renderFunc(func(v any) error {
+			_, err := fmt.Fprintf(w, "%s: %v\n", arg, v)
+			return err
+		}), nil
+	})
+
+	tests := []struct {
+		name          string
+		args          []string
+		formats       []naistrix.OutputFormat
+		expected      string
+		errorContains string
+	}{
+		{
+			name:     "default format",
+			args:     []string{"test"},
+			expected: "Name\n----\nfoo \n",
+		},
+		{
+			name:     "default format declared by command",
+			args:     []string{"test"},
+			formats:  []naistrix.OutputFormat{naistrix.OutputFormatYAML, naistrix.OutputFormatJSON},
+			expected: "- name: foo\n",
+		},
+		{
+			name:     "json",
+			args:     []string{"test", "--output", "json"},
+			expected: "[\n  {\n    \"Name\": \"foo\"\n  }\n]\n",
+		},
+		{
+			name:     "yaml",
+			args:     []string{"test", "--output", "yaml"},
+			expected: "- name: foo\n",
+		},
+		{
+			name:     "ndjson",
+			args:     []string{"test", "--output", "ndjson"},
+			expected: "{\"Name\":\"foo\"}\n",
+		},
+		{
+			name:     "json with query",
+			args:     []string{"test", "--output", "json=.[0].Name"},
+			expected: "\"foo\"\n",
+		},
+		{
+			name:     "jsonpath",
+			args:     []string{"test", "--output", "jsonpath=name: {.[*].Name}"},
+			expected: "name: foo\n",
+		},
+		{
+			name:          "jsonpath without template",
+			args:          []string{"test", "--output", "jsonpath"},
+			errorContains: "requires a template",
+		},
+		{
+			name:          "invalid query",
+			args:          []string{"test", "--output", "yaml=.[0"},
+			errorContains: `Invalid query ".[0": expected ] at position 3`,
+		},
+		{
+			name:     "go template",
+			args:     []string{"test", "--output", `go-template={{range .}}{{.Name | upper}}{{"\n"}}{{end}}`},
+			expected: "FOO\n",
+		},
+		{
+			name:          "invalid go template",
+			args:          []string{"test", "--output", "go-template={{.Missing}}"},
+			errorContains: "Invalid template: ",
+		},
+		{
+			name:     "csv",
+			args:     []string{"test", "--output", "csv"},
+			expected: "Name\nfoo\n",
+		},
+		{
+			name:     "custom format with argument",
+			args:     []string{"test", "--output", "custom=prefix"},
+			expected: "prefix: [{foo}]\n",
+		},
+		{
+			name:          "custom format with invalid argument",
+			args:          []string{"test", "--output", "custom"},
+			errorContains: "missing argument",
+		},
+		{
+			name:          "unknown format",
+			args:          []string{"test", "--output", "xml"},
+			errorContains: `Unsupported output format "xml", must be one of: csv, custom, go-template, json, jsonpath, ndjson, table, tsv, wide, yaml`,
+		},
+		{
+			name:          "format not supported by command",
+			args:          []string{"test", "--output", "table"},
+			formats:       []naistrix.OutputFormat{naistrix.OutputFormatJSON},
+			errorContains: `Unsupported output format "table", must be one of: json`,
+		},
+	}
+
+	pterm.DisableStyling()
+	defer pterm.EnableStyling()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			app, _, err := naistrix.NewApplication("app", "title", "v0.0.0", naistrix.ApplicationWithWriter(&buf), customFormat)
+			if err != nil {
+				t.Fatalf("unable to create application: %v", err)
+			}
+
+			err = app.AddCommand(&naistrix.Command{
+				Name:          "test",
+				Title:         "Test command",
+				OutputFormats: tt.formats,
+				RunFunc: func(_ context.Context, _ *naistrix.Arguments, out *naistrix.OutputWriter) error {
+					return out.Render([]row{{Name: "foo"}})
+				},
+			})
+			if err != nil {
+				t.Fatalf("unable to add command: %v", err)
+			}
+
+			err = app.Run(naistrix.RunWithArgs(tt.args))
+			if tt.errorContains != "" {
+				if err == nil {
+					t.Fatalf("expected error")
+				} else if !strings.Contains(err.Error(), tt.errorContains) {
+					t.Fatalf("expected error message to contain %q, got: %q", tt.errorContains, err.Error())
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			if actual := buf.String(); actual != tt.expected {
+				t.Errorf("expected output %q, got %q", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestOutputWriter_RenderValidatesFormatBeforeRun(t *testing.T) {
+	tests := []struct {
+		name          string
+		format        string
+		errorContains string
+	}{
+		{
+			name:          "invalid json query",
+			format:        "json=.[",
+			errorContains: `Invalid query ".[": `,
+		},
+		{
+			name:          "invalid yaml query",
+			format:        "yaml=.[0",
+			errorContains: `Invalid query ".[0": expected ] at position 3`,
+		},
+		{
+			name:          "invalid jsonpath template",
+			format:        "jsonpath={.[0}",
+			errorContains: "Invalid query ",
+		},
+		{
+			name:          "jsonpath without template",
+			format:        "jsonpath",
+			errorContains: "requires a template",
+		},
+		{
+			name:          "invalid go template",
+			format:        "go-template={{.Name",
+			errorContains: "Invalid template: ",
+		},
+		{
+			name:          "go template with unknown function",
+			format:        "go-template={{.Name | shout}}",
+			errorContains: `function "shout" not defined`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app, _, err := naistrix.NewApplication("app", "title", "v0.0.0", naistrix.ApplicationWithWriter(io.Discard))
+			if err != nil {
+				t.Fatalf("unable to create application: %v", err)
+			}
+
+			ran := false
+			err = app.AddCommand(&naistrix.Command{
+				Name:  "test",
+				Title: "Test command",
+				RunFunc: func(_ context.Context, _ *naistrix.Arguments, out *naistrix.OutputWriter) error {
+					ran = true
+					return out.Render([]string{"foo"})
+				},
+			})
+			if err != nil {
+				t.Fatalf("unable to add command: %v", err)
+			}
+
+			err = app.Run(naistrix.RunWithArgs([]string{"test", "--output", tt.format}))
+			if err == nil || !strings.Contains(err.Error(), tt.errorContains) {
+				t.Fatalf("expected error message to contain %q, got: %v", tt.errorContains, err)
+			}
+
+			if ran {
+				t.Fatalf("expected the command not to be run")
+			}
+		})
+	}
+}
+
+func TestOutputWriter_RenderKeepsErrorsFromCommands(t *testing.T) {
+	app, _, err := naistrix.NewApplication("app", "title", "v0.0.0", naistrix.ApplicationWithWriter(io.Discard))
+	if err != nil {
+		t.Fatalf("unable to create application: %v", err)
+	}
+
+	err = app.AddCommand(&naistrix.Command{
+		Name:  "test",
+		Title: "Test command",
+		RunFunc: func(context.Context, *naistrix.Arguments, *naistrix.OutputWriter) error {
+			_, err := output.ParseQuery(".[")
+			return fmt.Errorf("invalid query in the configuration: %w", err)
+		},
+	})
+	if err != nil {
+		t.Fatalf("unable to add command: %v", err)
+	}
+
+	err = app.Run(naistrix.RunWithArgs([]string{"test"}))
+	if contains := "invalid query in the configuration: "; err == nil || !strings.Contains(err.Error(), contains) {
+		t.Fatalf("expected error message to contain %q, got: %v", contains, err)
+	}
+
+	if _, ok := errors.AsType[*output.QueryError](err); !ok {
+		t.Fatalf("expected the query error to be kept, got: %v", err)
+	}
+}
+
+func TestOutputWriter_RenderFormatsInHelpAndCompletion(t *testing.T) {
+	run := func(args ...string) string {
+		t.Helper()
+
+		var buf bytes.Buffer
+		app, _, err := naistrix.NewApplication("app", "title", "v0.0.0", naistrix.ApplicationWithWriter(&buf))
+		if err != nil {
+			t.Fatalf("unable to create application: %v", err)
 		}
+
+		err = app.AddCommand(&naistrix.Command{
+			Name:          "test",
+			Title:         "Test command",
+			OutputFormats: []naistrix.OutputFormat{naistrix.OutputFormatJSON, naistrix.OutputFormatYAML},
+			RunFunc:       noop,
+		})
+		if err != nil {
+			t.Fatalf("unable to add command: %v", err)
+		}
+
+		if err := app.Run(naistrix.RunWithArgs(args)); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		return buf.String()
+	}
+
+	if help, contains := run("test", "-h"), "One of: json, yaml."; !strings.Contains(help, contains) {
+		t.Fatalf("expected help text to contain %q, got %q", contains, help)
+	}
+
+	if completions, expected := run("__complete", "test", "--output", ""), "json\nyaml\n:4\n"; completions != expected {
+		t.Fatalf("expected completions to be %q, got %q", expected, completions)
+	}
+}
+
+func TestCommand_UnknownOutputFormat(t *testing.T) {
+	app, _, err := naistrix.NewApplication("app", "title", "v0.0.0")
+	if err != nil {
+		t.Fatalf("unable to create application: %v", err)
+	}
+
+	err = app.AddCommand(&naistrix.Command{
+		Name:          "test",
+		Title:         "Test command",
+		OutputFormats: []naistrix.OutputFormat{"xml"},
+		RunFunc:       noop,
+	})
+	if err == nil {
+		t.Fatalf("expected error")
+	} else if contains := `declares unknown output format "xml"`; !strings.Contains(err.Error(), contains) {
+		t.Fatalf("expected error message to contain %q, got: %q", contains, err.Error())
+	}
+}
+
+// renderFunc is an adapter to allow the use of ordinary functions as renderers.
+type renderFunc func(v any) error
+
+func (f renderFunc) Render(v any) error {
+	return f(v)
+}
+
+func TestOutputWriter_TableFlags(t *testing.T) {
+	type row struct {
+		Name string
+		Age  int
+		Team string `wide:"true"`
+	}
+
+	data := []row{{Name: "foo", Age: 2, Team: "a"}, {Name: "bar", Age: 10, Team: "b"}, {Name: "baz", Age: 1, Team: "c"}}
+
+	tests := []struct {
+		name          string
+		args          []string
+		render        func(out *naistrix.OutputWriter) error
+		expected      string
+		errorContains string
+	}{
+		{
+			name: "render",
+			args: []string{"test", "--columns", "name", "--sort", "-age"},
+			render: func(out *naistrix.OutputWriter) error {
+				return out.Render(data)
+			},
+			expected: "Name\n----\nbar \nfoo \nbaz \n",
+		},
+		{
+			name: "table overrides command options",
+			args: []string{"test", "--columns", "age,name", "--filter", "name!=foo"},
+			render: func(out *naistrix.OutputWriter) error {
+				return out.Table(output.TableWithColumns("Name")).Render(data)
+			},
+			expected: "Age | Name\n----------\n10  | bar \n1   | baz \n",
+		},
+		{
+			name: "csv",
+			args: []string{"test", "--output", "csv", "--columns", "team,name", "--filter", "age!=2", "--sort", "name"},
+			render: func(out *naistrix.OutputWriter) error {
+				return out.Render(data)
+			},
+			expected: "Team,Name\nb,bar\nc,baz\n",
+		},
+		{
+			name: "tsv",
+			args: []string{"test", "--output", "tsv", "--columns", "name,age", "--sort", "-age"},
+			render: func(out *naistrix.OutputWriter) error {
+				return out.Render(data)
+			},
+			expected: "Name\tAge\nbar\t10\nfoo\t2\nbaz\t1\n",
+		},
+		{
+			name: "ignored by other formats",
+			args: []string{"test", "--columns", "name", "--output", "ndjson"},
+			render: func(out *naistrix.OutputWriter) error {
+				return out.Render(data[:1])
+			},
+			expected: "{\"Name\":\"foo\",\"Age\":2,\"Team\":\"a\"}\n",
+		},
+		{
+			name: "wide",
+			args: []string{"test", "--output", "wide", "--filter", "team=a"},
+			render: func(out *naistrix.OutputWriter) error {
+				return out.Render(data)
+			},
+			expected: "Name | Age | Team\n-----------------\nfoo  | 2   | a   \n",
+		},
+		{
+			name: "wide columns are left out by default",
+			args: []string{"test", "--filter", "team=a"},
+			render: func(out *naistrix.OutputWriter) error {
+				return out.Table().Render(data)
+			},
+			expected: "Name | Age\n----------\nfoo  | 2  \n",
+		},
+		{
+			name: "unknown column",
+			args: []string{"test", "--sort", "size"},
+			render: func(out *naistrix.OutputWriter) error {
+				return out.Render(data)
+			},
+			errorContains: `unknown column "size"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			app, _, err := naistrix.NewApplication("app", "title", "v0.0.0", naistrix.ApplicationWithWriter(&buf))
+			if err != nil {
+				t.Fatalf("unable to create application: %v", err)
+			}
+
+			err = app.AddCommand(&naistrix.Command{
+				Name:  "test",
+				Title: "Test command",
+				RunFunc: func(_ context.Context, _ *naistrix.Arguments, out *naistrix.OutputWriter) error {
+					return tt.render(out)
+				},
+			})
+			if err != nil {
+				t.Fatalf("unable to add command: %v", err)
+			}
+
+			err = app.Run(naistrix.RunWithArgs(tt.args))
+			if tt.errorContains != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errorContains) {
+					t.Fatalf("expected error message to contain %q, got: %v", tt.errorContains, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+
+			if actual := buf.String(); actual != tt.expected {
+				t.Errorf("expected output %q, got %q", tt.expected, actual)
+			}
+		})
 	}
 }